@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/EclesioMeloJunior/alang/token"
@@ -14,6 +15,8 @@ var (
 	_ Statement = (*ReturnStatement)(nil)
 	_ Statement = (*ExpressionStatement)(nil)
 	_ Statement = (*BlockStatement)(nil)
+	_ Statement = (*ImportStatement)(nil)
+	_ Statement = (*ThrowStatement)(nil)
 
 	_ Expression = (*Identifier)(nil)
 	_ Expression = (*BooleanLiteral)(nil)
@@ -23,11 +26,22 @@ var (
 	_ Expression = (*IfExpression)(nil)
 	_ Expression = (*FunctionLiteral)(nil)
 	_ Expression = (*CallExpression)(nil)
+	_ Expression = (*StringLiteral)(nil)
+	_ Expression = (*FloatLiteral)(nil)
+	_ Expression = (*ArrayLiteral)(nil)
+	_ Expression = (*HashLiteral)(nil)
+	_ Expression = (*IndexExpression)(nil)
+	_ Expression = (*SelectorExpression)(nil)
+	_ Expression = (*TryExpression)(nil)
 )
 
 type Node interface {
 	TokenLiteral() string
 	fmt.Stringer
+
+	// Pos is where the node starts in the source, the
+	// zero position when the node was not parsed from a source
+	Pos() token.Position
 }
 
 type Statement interface {
@@ -52,6 +66,14 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 	for _, s := range p.Statements {
@@ -64,6 +86,7 @@ func (p *Program) String() string {
 type LetStatement struct {
 	Token token.Token // the `let` token
 	Name  *Identifier
+	Type  TypeExpression // nil when the name is not annotated
 	Value Expression
 }
 
@@ -71,10 +94,16 @@ func (ls *LetStatement) statementNode() {}
 func (ls *LetStatement) TokenLiteral() string {
 	return ls.Token.Literal
 }
+func (ls *LetStatement) Pos() token.Position {
+	return ls.Token.Pos
+}
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
-	out.WriteString(ls.TokenLiteral() + " ")
+	out.WriteString("let ")
 	out.WriteString(ls.Name.String())
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -87,12 +116,23 @@ func (ls *LetStatement) String() string {
 type Identifier struct {
 	Token token.Token
 	Value string
+
+	// Resolved, Depth and Slot are set by the resolver when the name is
+	// bound in a function or catch scope: the binding is in the Slot of
+	// the scope Depth levels out of the scope of the identifier. The global
+	// names and the builtins are not resolved, they are looked up by name
+	Resolved bool
+	Depth    int
+	Slot     int
 }
 
 func (i *Identifier) expressionNode() {}
 func (i *Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
+func (i *Identifier) Pos() token.Position {
+	return i.Token.Pos
+}
 func (i *Identifier) String() string {
 	return i.Value
 }
@@ -106,9 +146,12 @@ func (rs *ReturnStatement) statementNode() {}
 func (rs *ReturnStatement) TokenLiteral() string {
 	return rs.Token.Literal
 }
+func (rs *ReturnStatement) Pos() token.Position {
+	return rs.Token.Pos
+}
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
-	out.WriteString(rs.Token.Literal + " ")
+	out.WriteString("return ")
 
 	if rs.Value != nil {
 		out.WriteString(rs.Value.String())
@@ -127,9 +170,15 @@ func (es *ExpressionStatement) statementNode() {}
 func (es *ExpressionStatement) TokenLiteral() string {
 	return es.Token.Literal
 }
+func (es *ExpressionStatement) Pos() token.Position {
+	return es.Token.Pos
+}
+
+// String ends the expression with a semicolon, otherwise the next
+// statement could be read as part of it, eg. `f` followed by `(x)`
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
-		return es.Expression.String()
+		return es.Expression.String() + ";"
 	}
 	return ""
 }
@@ -143,8 +192,11 @@ func (bl *BooleanLiteral) expressionNode() {}
 func (bl *BooleanLiteral) TokenLiteral() string {
 	return bl.Token.Literal
 }
+func (bl *BooleanLiteral) Pos() token.Position {
+	return bl.Token.Pos
+}
 func (bl *BooleanLiteral) String() string {
-	return bl.Token.Literal
+	return literal(bl.Token, strconv.FormatBool(bl.Value))
 }
 
 type IntegerLiteral struct {
@@ -156,8 +208,42 @@ func (il *IntegerLiteral) expressionNode() {}
 func (il *IntegerLiteral) TokenLiteral() string {
 	return il.Token.Literal
 }
+func (il *IntegerLiteral) Pos() token.Position {
+	return il.Token.Pos
+}
 func (il *IntegerLiteral) String() string {
-	return il.Token.Literal
+	return literal(il.Token, strconv.FormatInt(il.Value, 10))
+}
+
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
+func (fl *FloatLiteral) String() string {
+	value := strconv.FormatFloat(fl.Value, 'f', -1, 64)
+	if !strings.Contains(value, ".") {
+		value += ".0"
+	}
+
+	return literal(fl.Token, value)
+}
+
+// literal returns the literal as written in the source, or the
+// value for the literals built without a token, eg. by Modify
+func literal(tok token.Token, value string) string {
+	if tok.Literal == "" {
+		return value
+	}
+
+	return tok.Literal
 }
 
 type PrefixExpression struct {
@@ -170,6 +256,9 @@ func (pe *PrefixExpression) expressionNode() {}
 func (pe *PrefixExpression) TokenLiteral() string {
 	return pe.Token.Literal
 }
+func (pe *PrefixExpression) Pos() token.Position {
+	return pe.Token.Pos
+}
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -192,6 +281,11 @@ func (ie *InfixExpression) expressionNode() {}
 func (ie *InfixExpression) TokenLiteral() string {
 	return ie.Token.Literal
 }
+
+// Pos is the start of the left operand, the operator position is in the Token
+func (ie *InfixExpression) Pos() token.Position {
+	return ie.Left.Pos()
+}
 func (ie *InfixExpression) String() string {
 	var out bytes.Buffer
 
@@ -215,6 +309,9 @@ func (ie *IfExpression) expressionNode() {}
 func (ie *IfExpression) TokenLiteral() string {
 	return ie.Token.Literal
 }
+func (ie *IfExpression) Pos() token.Position {
+	return ie.Token.Pos
+}
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 
@@ -242,37 +339,68 @@ func (bs *BlockStatement) statementNode() {}
 func (bs *BlockStatement) TokenLiteral() string {
 	return bs.Token.Literal
 }
+func (bs *BlockStatement) Pos() token.Position {
+	return bs.Token.Pos
+}
 func (bs *BlockStatement) String() string {
-	var out bytes.Buffer
+	if len(bs.Statements) == 0 {
+		return "{}"
+	}
 
-	for _, stmt := range bs.Statements {
-		out.WriteString(stmt.String())
+	stmts := make([]string, len(bs.Statements))
+	for idx, stmt := range bs.Statements {
+		stmts[idx] = stmt.String()
 	}
 
-	return out.String()
+	return "{ " + strings.Join(stmts, " ") + " }"
 }
 
 type FunctionLiteral struct {
 	Token      token.Token // the `fn` token
 	Parameters []*Identifier
 	Body       *BlockStatement
+
+	// ParameterTypes are nil when no parameter is annotated, otherwise
+	// they are as many as the parameters, nil for the ones not annotated
+	ParameterTypes []TypeExpression
+	Result         TypeExpression
+
+	// Scope is the scope of the calls, set by the resolver
+	Scope *Scope
+}
+
+// ParameterType returns the annotation of the parameter at idx, or nil
+func (fl *FunctionLiteral) ParameterType(idx int) TypeExpression {
+	if idx < len(fl.ParameterTypes) {
+		return fl.ParameterTypes[idx]
+	}
+
+	return nil
 }
 
 func (fl *FunctionLiteral) expressionNode() {}
 func (fl *FunctionLiteral) TokenLiteral() string {
 	return fl.Token.Literal
 }
+func (fl *FunctionLiteral) Pos() token.Position {
+	return fl.Token.Pos
+}
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 	params := make([]string, len(fl.Parameters))
 	for idx, identifier := range fl.Parameters {
 		params[idx] = identifier.String()
+		if typ := fl.ParameterType(idx); typ != nil {
+			params[idx] += ": " + typ.String()
+		}
 	}
 
-	out.WriteString(fl.TokenLiteral())
-	out.WriteString("(")
+	out.WriteString("fn(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(")")
+	out.WriteString(") ")
+	if fl.Result != nil {
+		out.WriteString("-> " + fl.Result.String() + " ")
+	}
 	out.WriteString(fl.Body.String())
 
 	return out.String()
@@ -282,12 +410,22 @@ type CallExpression struct {
 	Token     token.Token // the `(` token
 	Function  Expression
 	Arguments []Expression
+
+	// Tail is set when the evaluation of the program starts if the call
+	// is in tail position of a function body, its value is the value the
+	// function returns so the function can be left before calling it
+	Tail bool
 }
 
 func (ce *CallExpression) expressionNode() {}
 func (ce *CallExpression) TokenLiteral() string {
 	return ce.Token.Literal
 }
+
+// Pos is the start of the callee, the `(` position is in the Token
+func (ce *CallExpression) Pos() token.Position {
+	return ce.Function.Pos()
+}
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -303,3 +441,221 @@ func (ce *CallExpression) String() string {
 
 	return out.String()
 }
+
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl *StringLiteral) expressionNode() {}
+func (sl *StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+func (sl *StringLiteral) Pos() token.Position {
+	return sl.Token.Pos
+}
+func (sl *StringLiteral) String() string {
+	return quote(sl.Value)
+}
+
+// quote wraps the string in double quotes escaping the
+// same sequences the lexer understands
+func quote(value string) string {
+	var out bytes.Buffer
+
+	out.WriteByte('"')
+	for idx := 0; idx < len(value); idx++ {
+		switch char := value[idx]; char {
+		case '"', '\\':
+			out.WriteByte('\\')
+			out.WriteByte(char)
+		case '\n':
+			out.WriteString("\\n")
+		case '\t':
+			out.WriteString("\\t")
+		case '\r':
+			out.WriteString("\\r")
+		default:
+			out.WriteByte(char)
+		}
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
+
+type ArrayLiteral struct {
+	Token    token.Token // the `[` token
+	Elements []Expression
+}
+
+func (al *ArrayLiteral) expressionNode() {}
+func (al *ArrayLiteral) TokenLiteral() string {
+	return al.Token.Literal
+}
+func (al *ArrayLiteral) Pos() token.Position {
+	return al.Token.Pos
+}
+func (al *ArrayLiteral) String() string {
+	elements := make([]string, len(al.Elements))
+	for idx, element := range al.Elements {
+		elements[idx] = element.String()
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+type HashLiteral struct {
+	Token token.Token // the `{` token
+	Pairs []HashPair  // in the order they were written
+}
+
+func (hl *HashLiteral) expressionNode() {}
+func (hl *HashLiteral) TokenLiteral() string {
+	return hl.Token.Literal
+}
+func (hl *HashLiteral) Pos() token.Position {
+	return hl.Token.Pos
+}
+func (hl *HashLiteral) String() string {
+	pairs := make([]string, len(hl.Pairs))
+	for idx, pair := range hl.Pairs {
+		pairs[idx] = pair.Key.String() + ": " + pair.Value.String()
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+type IndexExpression struct {
+	Token token.Token // the `[` token
+	Left  Expression
+	Index Expression
+}
+
+func (ie *IndexExpression) expressionNode() {}
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+// Pos is the start of the indexed expression, the `[` position is in the Token
+func (ie *IndexExpression) Pos() token.Position {
+	return ie.Left.Pos()
+}
+func (ie *IndexExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ie.Left.String())
+	out.WriteString("[")
+	out.WriteString(ie.Index.String())
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// SelectorExpression is the access to a field or
+// method of a value, eg. `user.Name` or `user.Greet()`
+type SelectorExpression struct {
+	Token    token.Token // the `.` token
+	Left     Expression
+	Selector *Identifier
+}
+
+func (se *SelectorExpression) expressionNode() {}
+func (se *SelectorExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+
+// Pos is the start of the left expression, the `.` position is in the Token
+func (se *SelectorExpression) Pos() token.Position {
+	return se.Left.Pos()
+}
+func (se *SelectorExpression) String() string {
+	return se.Left.String() + "." + se.Selector.String()
+}
+
+// ImportStatement binds the module in Path to the
+// Alias name, eg. `import "lib/math.al" as m;`
+type ImportStatement struct {
+	Token token.Token // the `import` token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode() {}
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+func (is *ImportStatement) Pos() token.Position {
+	return is.Token.Pos
+}
+func (is *ImportStatement) String() string {
+	return "import " + is.Path.String() + " as " + is.Alias.String() + ";"
+}
+
+type ThrowStatement struct {
+	Token token.Token // the `throw` token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode() {}
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+func (ts *ThrowStatement) Pos() token.Position {
+	return ts.Token.Pos
+}
+func (ts *ThrowStatement) String() string {
+	return "throw " + ts.Value.String() + ";"
+}
+
+// TryExpression evaluates to the value of the Block, or of the Catch
+// when the Block fails, at least one of Catch and Finally is present
+type TryExpression struct {
+	Token   token.Token // the `try` token
+	Block   *BlockStatement
+	Param   *Identifier // the name the caught error is bound to
+	Catch   *BlockStatement
+	Finally *BlockStatement
+
+	// CatchScope is the scope of the catch block, set by the resolver
+	CatchScope *Scope
+}
+
+// Scope holds the names bound in the environment a function call or a catch
+// block creates, indexed by their slots, see Identifier. The same scope is
+// shared by every environment created for the node
+type Scope struct {
+	Names []string
+}
+
+func (te *TryExpression) expressionNode() {}
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+func (te *TryExpression) Pos() token.Position {
+	return te.Token.Pos
+}
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(te.Block.String())
+
+	if te.Catch != nil {
+		out.WriteString(" catch (" + te.Param.String() + ") ")
+		out.WriteString(te.Catch.String())
+	}
+
+	if te.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(te.Finally.String())
+	}
+
+	return out.String()
+}
@@ -40,3 +40,29 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong.\n\texpected = %q\n\tgot = %q", expected_prog_str, program_str)
 	}
 }
+
+func TestStringWithoutTokens(t *testing.T) {
+	// the nodes built by hand, eg. by Modify, print the keywords and
+	// the values of the literals even without their tokens
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.ReturnStatement{
+				Value: &ast.IfExpression{
+					Condition: &ast.BooleanLiteral{Value: true},
+					Consequence: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Expression: &ast.IntegerLiteral{Value: 10}},
+					}},
+					Alternative: &ast.BlockStatement{Statements: []ast.Statement{
+						&ast.ExpressionStatement{Expression: &ast.FloatLiteral{Value: 2}},
+					}},
+				},
+			},
+			&ast.ExpressionStatement{Expression: &ast.FunctionLiteral{Body: &ast.BlockStatement{}}},
+		},
+	}
+
+	const expected = "return if (true) { 10; } else { 2.0; };fn() {};"
+	if got := program.String(); got != expected {
+		t.Errorf("program.String() wrong.\n\texpected = %q\n\tgot = %q", expected, got)
+	}
+}
@@ -0,0 +1,581 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// jsonNode is the JSON form of every node, kind is the name of the node
+// type, eg. "LetStatement", and the other fields are the ones of the node
+// with their names in lower camel case. Missing children and empty lists
+// are omitted, the position of the node is the one of its token
+type jsonNode struct {
+	Kind  string       `json:"kind"`
+	Token *token.Token `json:"token,omitempty"`
+
+	Name     *jsonNode `json:"name,omitempty"`
+	Left     *jsonNode `json:"left,omitempty"`
+	Operator string    `json:"operator,omitempty"`
+	Right    *jsonNode `json:"right,omitempty"`
+
+	// Value is a child node for the let, return and throw statements and
+	// the hash types, and the literal value for the identifiers, the
+	// literals and the named types
+	Value json.RawMessage `json:"value,omitempty"`
+
+	Expression  *jsonNode `json:"expression,omitempty"`
+	Condition   *jsonNode `json:"condition,omitempty"`
+	Consequence *jsonNode `json:"consequence,omitempty"`
+	Alternative *jsonNode `json:"alternative,omitempty"`
+	Function    *jsonNode `json:"function,omitempty"`
+	Body        *jsonNode `json:"body,omitempty"`
+	Index       *jsonNode `json:"index,omitempty"`
+	Selector    *jsonNode `json:"selector,omitempty"`
+	Path        *jsonNode `json:"path,omitempty"`
+	Alias       *jsonNode `json:"alias,omitempty"`
+	Block       *jsonNode `json:"block,omitempty"`
+	Param       *jsonNode `json:"param,omitempty"`
+	Catch       *jsonNode `json:"catch,omitempty"`
+	Finally     *jsonNode `json:"finally,omitempty"`
+	Type        *jsonNode `json:"type,omitempty"`
+	Result      *jsonNode `json:"result,omitempty"`
+	Element     *jsonNode `json:"element,omitempty"`
+	Key         *jsonNode `json:"key,omitempty"`
+
+	Statements []*jsonNode    `json:"statements,omitempty"`
+	Parameters []*jsonNode    `json:"parameters,omitempty"`
+	Arguments  []*jsonNode    `json:"arguments,omitempty"`
+	Elements   []*jsonNode    `json:"elements,omitempty"`
+	Pairs      []jsonHashPair `json:"pairs,omitempty"`
+
+	// ParameterTypes has a null for each parameter not annotated
+	ParameterTypes []*jsonNode `json:"parameterTypes,omitempty"`
+}
+
+type jsonHashPair struct {
+	Key   *jsonNode `json:"key"`
+	Value *jsonNode `json:"value"`
+}
+
+// EncodeJSON serializes the tree, DecodeJSON reads it back
+func EncodeJSON(node Node) ([]byte, error) {
+	encoded, err := toJSON(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(encoded)
+}
+
+// DecodeJSON rebuilds the tree serialized by EncodeJSON
+func DecodeJSON(data []byte) (Node, error) {
+	var decoded jsonNode
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	return decoded.node()
+}
+
+func toJSON(node Node) (*jsonNode, error) {
+	var err error
+	encode := func(child Node) *jsonNode {
+		if err != nil {
+			return nil
+		}
+
+		var encoded *jsonNode
+		encoded, err = toJSON(child)
+		return encoded
+	}
+
+	value := func(v interface{}) json.RawMessage {
+		if err != nil {
+			return nil
+		}
+
+		var raw json.RawMessage
+		raw, err = json.Marshal(v)
+		return raw
+	}
+
+	out := &jsonNode{Kind: kindOf(node)}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, stmt := range n.Statements {
+			out.Statements = append(out.Statements, encode(stmt))
+		}
+		return out, err
+
+	case *LetStatement:
+		out.Token = &n.Token
+		out.Name = encode(n.Name)
+		if n.Type != nil {
+			out.Type = encode(n.Type)
+		}
+		if n.Value != nil {
+			out.Value = value(encode(n.Value))
+		}
+
+	case *ReturnStatement:
+		out.Token = &n.Token
+		if n.Value != nil {
+			out.Value = value(encode(n.Value))
+		}
+
+	case *ExpressionStatement:
+		out.Token = &n.Token
+		if n.Expression != nil {
+			out.Expression = encode(n.Expression)
+		}
+
+	case *BlockStatement:
+		out.Token = &n.Token
+		for _, stmt := range n.Statements {
+			out.Statements = append(out.Statements, encode(stmt))
+		}
+
+	case *ImportStatement:
+		out.Token = &n.Token
+		out.Path = encode(n.Path)
+		out.Alias = encode(n.Alias)
+
+	case *ThrowStatement:
+		out.Token = &n.Token
+		out.Value = value(encode(n.Value))
+
+	case *Identifier:
+		out.Token = &n.Token
+		out.Value = value(n.Value)
+
+	case *BooleanLiteral:
+		out.Token = &n.Token
+		out.Value = value(n.Value)
+
+	case *IntegerLiteral:
+		out.Token = &n.Token
+		out.Value = value(n.Value)
+
+	case *FloatLiteral:
+		out.Token = &n.Token
+		out.Value = value(n.Value)
+
+	case *StringLiteral:
+		out.Token = &n.Token
+		out.Value = value(n.Value)
+
+	case *PrefixExpression:
+		out.Token = &n.Token
+		out.Operator = n.Operator
+		out.Right = encode(n.Right)
+
+	case *InfixExpression:
+		out.Token = &n.Token
+		out.Left = encode(n.Left)
+		out.Operator = n.Operator
+		out.Right = encode(n.Right)
+
+	case *IfExpression:
+		out.Token = &n.Token
+		out.Condition = encode(n.Condition)
+		out.Consequence = encode(n.Consequence)
+		if n.Alternative != nil {
+			out.Alternative = encode(n.Alternative)
+		}
+
+	case *FunctionLiteral:
+		out.Token = &n.Token
+		for _, param := range n.Parameters {
+			out.Parameters = append(out.Parameters, encode(param))
+		}
+		for _, typ := range n.ParameterTypes {
+			var encoded *jsonNode
+			if typ != nil {
+				encoded = encode(typ)
+			}
+			out.ParameterTypes = append(out.ParameterTypes, encoded)
+		}
+		if n.Result != nil {
+			out.Result = encode(n.Result)
+		}
+		out.Body = encode(n.Body)
+
+	case *CallExpression:
+		out.Token = &n.Token
+		out.Function = encode(n.Function)
+		for _, arg := range n.Arguments {
+			out.Arguments = append(out.Arguments, encode(arg))
+		}
+
+	case *ArrayLiteral:
+		out.Token = &n.Token
+		for _, element := range n.Elements {
+			out.Elements = append(out.Elements, encode(element))
+		}
+
+	case *HashLiteral:
+		out.Token = &n.Token
+		for _, pair := range n.Pairs {
+			out.Pairs = append(out.Pairs, jsonHashPair{Key: encode(pair.Key), Value: encode(pair.Value)})
+		}
+
+	case *IndexExpression:
+		out.Token = &n.Token
+		out.Left = encode(n.Left)
+		out.Index = encode(n.Index)
+
+	case *SelectorExpression:
+		out.Token = &n.Token
+		out.Left = encode(n.Left)
+		out.Selector = encode(n.Selector)
+
+	case *TryExpression:
+		out.Token = &n.Token
+		out.Block = encode(n.Block)
+		if n.Catch != nil {
+			out.Param = encode(n.Param)
+			out.Catch = encode(n.Catch)
+		}
+		if n.Finally != nil {
+			out.Finally = encode(n.Finally)
+		}
+
+	case *NamedType:
+		out.Token = &n.Token
+		out.Value = value(n.Name)
+
+	case *ArrayType:
+		out.Token = &n.Token
+		out.Element = encode(n.Element)
+
+	case *HashType:
+		out.Token = &n.Token
+		out.Key = encode(n.Key)
+		out.Value = value(encode(n.Value))
+
+	case *FunctionType:
+		out.Token = &n.Token
+		for _, param := range n.Parameters {
+			out.Parameters = append(out.Parameters, encode(param))
+		}
+		out.Result = encode(n.Result)
+
+	default:
+		return nil, fmt.Errorf("ast: cannot encode node type %T", node)
+	}
+
+	return out, err
+}
+
+// kindOf is the name of the node type without the package
+func kindOf(node Node) string {
+	kind := fmt.Sprintf("%T", node)
+	return kind[len("*ast."):]
+}
+
+func (j *jsonNode) node() (Node, error) {
+	d := &jsonDecoder{kind: j.Kind}
+
+	var tok token.Token
+	if j.Token != nil {
+		tok = *j.Token
+	}
+
+	var node Node
+	switch j.Kind {
+	case "Program":
+		program := &Program{Statements: []Statement{}}
+		for _, stmt := range j.Statements {
+			program.Statements = append(program.Statements, d.statement("statements", stmt))
+		}
+		node = program
+
+	case "LetStatement":
+		node = &LetStatement{
+			Token: tok,
+			Name:  d.identifier("name", j.Name),
+			Type:  d.optionalType("type", j.Type),
+			Value: d.expressionValue(j.Value),
+		}
+
+	case "ReturnStatement":
+		node = &ReturnStatement{Token: tok, Value: d.expressionValue(j.Value)}
+
+	case "ExpressionStatement":
+		node = &ExpressionStatement{Token: tok, Expression: d.expression("expression", j.Expression)}
+
+	case "BlockStatement":
+		block := &BlockStatement{Token: tok, Statements: []Statement{}}
+		for _, stmt := range j.Statements {
+			block.Statements = append(block.Statements, d.statement("statements", stmt))
+		}
+		node = block
+
+	case "ImportStatement":
+		path, _ := d.child("path", j.Path).(*StringLiteral)
+		if path == nil && d.err == nil {
+			d.err = fmt.Errorf("ast: ImportStatement.path must be a StringLiteral")
+		}
+		node = &ImportStatement{Token: tok, Path: path, Alias: d.identifier("alias", j.Alias)}
+
+	case "ThrowStatement":
+		node = &ThrowStatement{Token: tok, Value: d.expressionValue(j.Value)}
+
+	case "Identifier":
+		ident := &Identifier{Token: tok}
+		d.literal(j.Value, &ident.Value)
+		node = ident
+
+	case "BooleanLiteral":
+		boolean := &BooleanLiteral{Token: tok}
+		d.literal(j.Value, &boolean.Value)
+		node = boolean
+
+	case "IntegerLiteral":
+		integer := &IntegerLiteral{Token: tok}
+		d.literal(j.Value, &integer.Value)
+		node = integer
+
+	case "FloatLiteral":
+		float := &FloatLiteral{Token: tok}
+		d.literal(j.Value, &float.Value)
+		node = float
+
+	case "StringLiteral":
+		str := &StringLiteral{Token: tok}
+		d.literal(j.Value, &str.Value)
+		node = str
+
+	case "PrefixExpression":
+		node = &PrefixExpression{Token: tok, Operator: j.Operator, Right: d.expression("right", j.Right)}
+
+	case "InfixExpression":
+		node = &InfixExpression{
+			Token:    tok,
+			Left:     d.expression("left", j.Left),
+			Operator: j.Operator,
+			Right:    d.expression("right", j.Right),
+		}
+
+	case "IfExpression":
+		node = &IfExpression{
+			Token:       tok,
+			Condition:   d.expression("condition", j.Condition),
+			Consequence: d.block("consequence", j.Consequence),
+			Alternative: d.optionalBlock("alternative", j.Alternative),
+		}
+
+	case "FunctionLiteral":
+		function := &FunctionLiteral{Token: tok, Parameters: []*Identifier{}}
+		for _, param := range j.Parameters {
+			function.Parameters = append(function.Parameters, d.identifier("parameters", param))
+		}
+		for _, typ := range j.ParameterTypes {
+			function.ParameterTypes = append(function.ParameterTypes, d.optionalType("parameterTypes", typ))
+		}
+		function.Result = d.optionalType("result", j.Result)
+		function.Body = d.block("body", j.Body)
+		node = function
+
+	case "CallExpression":
+		call := &CallExpression{Token: tok, Function: d.expression("function", j.Function), Arguments: []Expression{}}
+		for _, arg := range j.Arguments {
+			call.Arguments = append(call.Arguments, d.expression("arguments", arg))
+		}
+		node = call
+
+	case "ArrayLiteral":
+		array := &ArrayLiteral{Token: tok, Elements: []Expression{}}
+		for _, element := range j.Elements {
+			array.Elements = append(array.Elements, d.expression("elements", element))
+		}
+		node = array
+
+	case "HashLiteral":
+		hash := &HashLiteral{Token: tok, Pairs: []HashPair{}}
+		for _, pair := range j.Pairs {
+			hash.Pairs = append(hash.Pairs, HashPair{
+				Key:   d.expression("pairs.key", pair.Key),
+				Value: d.expression("pairs.value", pair.Value),
+			})
+		}
+		node = hash
+
+	case "IndexExpression":
+		node = &IndexExpression{Token: tok, Left: d.expression("left", j.Left), Index: d.expression("index", j.Index)}
+
+	case "SelectorExpression":
+		node = &SelectorExpression{Token: tok, Left: d.expression("left", j.Left), Selector: d.identifier("selector", j.Selector)}
+
+	case "TryExpression":
+		try := &TryExpression{
+			Token:   tok,
+			Block:   d.block("block", j.Block),
+			Catch:   d.optionalBlock("catch", j.Catch),
+			Finally: d.optionalBlock("finally", j.Finally),
+		}
+		if try.Catch != nil {
+			try.Param = d.identifier("param", j.Param)
+		}
+		node = try
+
+	case "NamedType":
+		named := &NamedType{Token: tok}
+		d.literal(j.Value, &named.Name)
+		node = named
+
+	case "ArrayType":
+		node = &ArrayType{Token: tok, Element: d.typeExpression("element", j.Element)}
+
+	case "HashType":
+		node = &HashType{Token: tok, Key: d.typeExpression("key", j.Key), Value: d.typeValue(j.Value)}
+
+	case "FunctionType":
+		function := &FunctionType{Token: tok, Parameters: []TypeExpression{}}
+		for _, param := range j.Parameters {
+			function.Parameters = append(function.Parameters, d.typeExpression("parameters", param))
+		}
+		function.Result = d.typeExpression("result", j.Result)
+		node = function
+
+	default:
+		return nil, fmt.Errorf("ast: unknown node kind %q", j.Kind)
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return node, nil
+}
+
+// jsonDecoder decodes the children of a node of the kind,
+// keeping the first error found so the node is built in one go
+type jsonDecoder struct {
+	kind string
+	err  error
+}
+
+func (d *jsonDecoder) child(field string, j *jsonNode) Node {
+	if d.err != nil {
+		return nil
+	}
+
+	if j == nil {
+		d.err = fmt.Errorf("ast: %s.%s is missing", d.kind, field)
+		return nil
+	}
+
+	node, err := j.node()
+	if err != nil {
+		d.err = err
+		return nil
+	}
+
+	return node
+}
+
+func (d *jsonDecoder) statement(field string, j *jsonNode) Statement {
+	stmt, ok := d.child(field, j).(Statement)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("ast: %s.%s must be a statement, got %s", d.kind, field, j.Kind)
+	}
+
+	return stmt
+}
+
+func (d *jsonDecoder) expression(field string, j *jsonNode) Expression {
+	exp, ok := d.child(field, j).(Expression)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("ast: %s.%s must be an expression, got %s", d.kind, field, j.Kind)
+	}
+
+	return exp
+}
+
+func (d *jsonDecoder) identifier(field string, j *jsonNode) *Identifier {
+	ident, ok := d.child(field, j).(*Identifier)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("ast: %s.%s must be an Identifier, got %s", d.kind, field, j.Kind)
+	}
+
+	return ident
+}
+
+func (d *jsonDecoder) block(field string, j *jsonNode) *BlockStatement {
+	block, ok := d.child(field, j).(*BlockStatement)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("ast: %s.%s must be a BlockStatement, got %s", d.kind, field, j.Kind)
+	}
+
+	return block
+}
+
+func (d *jsonDecoder) optionalBlock(field string, j *jsonNode) *BlockStatement {
+	if j == nil {
+		return nil
+	}
+
+	return d.block(field, j)
+}
+
+func (d *jsonDecoder) typeExpression(field string, j *jsonNode) TypeExpression {
+	typ, ok := d.child(field, j).(TypeExpression)
+	if !ok && d.err == nil {
+		d.err = fmt.Errorf("ast: %s.%s must be a type, got %s", d.kind, field, j.Kind)
+	}
+
+	return typ
+}
+
+func (d *jsonDecoder) optionalType(field string, j *jsonNode) TypeExpression {
+	if j == nil {
+		return nil
+	}
+
+	return d.typeExpression(field, j)
+}
+
+// typeValue decodes the value field holding a child type
+func (d *jsonDecoder) typeValue(raw json.RawMessage) TypeExpression {
+	if d.err != nil {
+		return nil
+	}
+
+	var j jsonNode
+	if err := json.Unmarshal(raw, &j); err != nil {
+		d.err = fmt.Errorf("ast: %s.value: %w", d.kind, err)
+		return nil
+	}
+
+	return d.typeExpression("value", &j)
+}
+
+// expressionValue decodes the value field holding a child expression
+func (d *jsonDecoder) expressionValue(raw json.RawMessage) Expression {
+	if d.err != nil || len(raw) == 0 {
+		return nil
+	}
+
+	var j jsonNode
+	if err := json.Unmarshal(raw, &j); err != nil {
+		d.err = fmt.Errorf("ast: %s.value: %w", d.kind, err)
+		return nil
+	}
+
+	return d.expression("value", &j)
+}
+
+// literal decodes the value field holding the value of a literal
+func (d *jsonDecoder) literal(raw json.RawMessage, v interface{}) {
+	if d.err != nil {
+		return
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		d.err = fmt.Errorf("ast: %s.value: %w", d.kind, err)
+	}
+}
@@ -0,0 +1,73 @@
+package ast_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	const input = `import "lib.al" as lib;
+let add = fn(a, b) { return a + b; };
+let noop = fn() { };
+if (!true) { add(1, 2.5) } else { [x, "s\n"][0] };
+if (false) { f() };
+{"k": lib.v, 1: []};
+try { throw -1; } catch (e) { e } finally { 0 };
+try { 1 } finally { 2 };`
+
+	program := parse(t, input)
+
+	encoded, err := ast.EncodeJSON(program)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	decoded, err := ast.DecodeJSON(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(program, decoded) {
+		t.Fatalf("decoded tree differs from the parsed one.\nexpected: %s\ngot: %s", program, decoded)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	encoded, err := ast.EncodeJSON(parse(t, `-x;`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	const expected = `{"kind":"Program","statements":[{"kind":"ExpressionStatement",` +
+		`"token":{"type":"-","literal":"-","pos":{"line":1,"column":1}},` +
+		`"expression":{"kind":"PrefixExpression","token":{"type":"-","literal":"-","pos":{"line":1,"column":1}},` +
+		`"operator":"-","right":{"kind":"Identifier","token":{"type":"IDENT","literal":"x","pos":{"line":1,"column":2}},"value":"x"}}}]}`
+
+	if string(encoded) != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, encoded)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"kind":"Unknown"}`, `ast: unknown node kind "Unknown"`},
+		{`{"kind":"PrefixExpression","operator":"-"}`, "ast: PrefixExpression.right is missing"},
+		{`{"kind":"Program","statements":[{"kind":"Identifier","value":"x"}]}`, "ast: Program.statements must be a statement, got Identifier"},
+		{`{"kind":"FunctionLiteral","body":{"kind":"Identifier","value":"x"}}`, "ast: FunctionLiteral.body must be a BlockStatement, got Identifier"},
+		{`{"kind":"IntegerLiteral","value":"1"}`, "ast: IntegerLiteral.value: json: cannot unmarshal string"},
+		{`[]`, "json: cannot unmarshal array"},
+	}
+
+	for _, tt := range tests {
+		_, err := ast.DecodeJSON([]byte(tt.input))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("%s\n\texpected error %q. got=%v", tt.input, tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ast
+
+import "fmt"
+
+// ModifierFunc returns the node that replaces the given one, or the node itself
+type ModifierFunc func(Node) Node
+
+// Modify rebuilds the tree bottom-up: the children of the node are modified
+// first, in the order they appear in the source, and then the node itself is
+// given to the modifier, whose result replaces it. The nodes are updated in
+// place, so the given tree should not be used after the call, only the returned
+// one. A replacement must fit the field it goes to, eg. the body of a function
+// can only be replaced by a *BlockStatement, otherwise Modify panics
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		modifyStatements(n.Statements, modifier)
+
+	case *LetStatement:
+		n.Name = modifyIdentifier(n.Name, modifier)
+		if n.Type != nil {
+			n.Type = modifyType(n.Type, modifier)
+		}
+		if n.Value != nil {
+			n.Value = modifyExpression(n.Value, modifier)
+		}
+
+	case *ReturnStatement:
+		if n.Value != nil {
+			n.Value = modifyExpression(n.Value, modifier)
+		}
+
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			n.Expression = modifyExpression(n.Expression, modifier)
+		}
+
+	case *BlockStatement:
+		modifyStatements(n.Statements, modifier)
+
+	case *ImportStatement:
+		n.Path = modifyString(n.Path, modifier)
+		n.Alias = modifyIdentifier(n.Alias, modifier)
+
+	case *ThrowStatement:
+		n.Value = modifyExpression(n.Value, modifier)
+
+	case *Identifier, *BooleanLiteral, *IntegerLiteral, *FloatLiteral, *StringLiteral:
+		// nothing to do
+
+	case *PrefixExpression:
+		n.Right = modifyExpression(n.Right, modifier)
+
+	case *InfixExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Right = modifyExpression(n.Right, modifier)
+
+	case *IfExpression:
+		n.Condition = modifyExpression(n.Condition, modifier)
+		n.Consequence = modifyBlock(n.Consequence, modifier)
+		if n.Alternative != nil {
+			n.Alternative = modifyBlock(n.Alternative, modifier)
+		}
+
+	case *FunctionLiteral:
+		for idx, param := range n.Parameters {
+			n.Parameters[idx] = modifyIdentifier(param, modifier)
+			if typ := n.ParameterType(idx); typ != nil {
+				n.ParameterTypes[idx] = modifyType(typ, modifier)
+			}
+		}
+		if n.Result != nil {
+			n.Result = modifyType(n.Result, modifier)
+		}
+		n.Body = modifyBlock(n.Body, modifier)
+
+	case *CallExpression:
+		n.Function = modifyExpression(n.Function, modifier)
+		modifyExpressions(n.Arguments, modifier)
+
+	case *ArrayLiteral:
+		modifyExpressions(n.Elements, modifier)
+
+	case *HashLiteral:
+		for idx, pair := range n.Pairs {
+			n.Pairs[idx] = HashPair{
+				Key:   modifyExpression(pair.Key, modifier),
+				Value: modifyExpression(pair.Value, modifier),
+			}
+		}
+
+	case *IndexExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Index = modifyExpression(n.Index, modifier)
+
+	case *SelectorExpression:
+		n.Left = modifyExpression(n.Left, modifier)
+		n.Selector = modifyIdentifier(n.Selector, modifier)
+
+	case *TryExpression:
+		n.Block = modifyBlock(n.Block, modifier)
+		if n.Param != nil {
+			n.Param = modifyIdentifier(n.Param, modifier)
+		}
+		if n.Catch != nil {
+			n.Catch = modifyBlock(n.Catch, modifier)
+		}
+		if n.Finally != nil {
+			n.Finally = modifyBlock(n.Finally, modifier)
+		}
+
+	case *NamedType:
+		// nothing to do
+
+	case *ArrayType:
+		n.Element = modifyType(n.Element, modifier)
+
+	case *HashType:
+		n.Key = modifyType(n.Key, modifier)
+		n.Value = modifyType(n.Value, modifier)
+
+	case *FunctionType:
+		for idx, param := range n.Parameters {
+			n.Parameters[idx] = modifyType(param, modifier)
+		}
+		n.Result = modifyType(n.Result, modifier)
+
+	default:
+		panic(fmt.Sprintf("ast.Modify: unexpected node type %T", n))
+	}
+
+	return modifier(node)
+}
+
+func modifyStatements(stmts []Statement, modifier ModifierFunc) {
+	for idx, stmt := range stmts {
+		node := Modify(stmt, modifier)
+		modified, ok := node.(Statement)
+		if !ok {
+			mismatch(stmt, node)
+		}
+
+		stmts[idx] = modified
+	}
+}
+
+func modifyExpressions(exps []Expression, modifier ModifierFunc) {
+	for idx, exp := range exps {
+		exps[idx] = modifyExpression(exp, modifier)
+	}
+}
+
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	node := Modify(exp, modifier)
+	modified, ok := node.(Expression)
+	if !ok {
+		mismatch(exp, node)
+	}
+
+	return modified
+}
+
+func modifyIdentifier(ident *Identifier, modifier ModifierFunc) *Identifier {
+	node := Modify(ident, modifier)
+	modified, ok := node.(*Identifier)
+	if !ok {
+		mismatch(ident, node)
+	}
+
+	return modified
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	node := Modify(block, modifier)
+	modified, ok := node.(*BlockStatement)
+	if !ok {
+		mismatch(block, node)
+	}
+
+	return modified
+}
+
+func modifyString(str *StringLiteral, modifier ModifierFunc) *StringLiteral {
+	node := Modify(str, modifier)
+	modified, ok := node.(*StringLiteral)
+	if !ok {
+		mismatch(str, node)
+	}
+
+	return modified
+}
+
+func modifyType(typ TypeExpression, modifier ModifierFunc) TypeExpression {
+	node := Modify(typ, modifier)
+	modified, ok := node.(TypeExpression)
+	if !ok {
+		mismatch(typ, node)
+	}
+
+	return modified
+}
+
+// mismatch panics when a replacement does not fit the field of the original node
+func mismatch(original, replacement Node) {
+	panic(fmt.Sprintf("ast.Modify: cannot replace %T with %T", original, replacement))
+}
@@ -0,0 +1,119 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+func TestModify(t *testing.T) {
+	turnOneIntoTwo := func(node ast.Node) ast.Node {
+		integer, ok := node.(*ast.IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+
+		return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "2"}, Value: 2}
+	}
+
+	tests := []string{
+		`1;`,
+		`let x = 1;`,
+		`fn() { return 1; };`,
+		`-1;`,
+		`1 + 1;`,
+		`if (1) { 1 } else { 1 };`,
+		`fn(a) { 1 };`,
+		`f(1, 1);`,
+		`[1, 1];`,
+		`{1: 1};`,
+		`a[1];`,
+		`[1].x;`,
+		`throw 1;`,
+		`try { 1 } catch (e) { 1 } finally { 1 };`,
+	}
+
+	for _, input := range tests {
+		expected := parse(t, strings.ReplaceAll(input, "1", "2")).String()
+
+		modified := ast.Modify(parse(t, input), turnOneIntoTwo)
+		if modified.String() != expected {
+			t.Errorf("%s\n\texpected %s. got=%s", input, expected, modified.String())
+		}
+	}
+}
+
+func TestModifyIsBottomUp(t *testing.T) {
+	var order []string
+	ast.Modify(parse(t, `let f = fn(x) { x + 1 };`), func(node ast.Node) ast.Node {
+		order = append(order, node.TokenLiteral())
+		return node
+	})
+
+	const expected = "f x x 1 + x { fn let let"
+	if got := strings.Join(order, " "); got != expected {
+		t.Fatalf("expected %q. got=%q", expected, got)
+	}
+}
+
+func TestModifyRenamesIdentifiers(t *testing.T) {
+	rename := func(node ast.Node) ast.Node {
+		if ident, ok := node.(*ast.Identifier); ok && ident.Value == "old" {
+			return &ast.Identifier{Token: token.Token{Type: token.IDENT, Literal: "new"}, Value: "new"}
+		}
+
+		return node
+	}
+
+	program := ast.Modify(parse(t, `let old = fn(old) { old.old }; old(1);`), rename)
+
+	const expected = "let new = fn(new) { new.new; };new(1);"
+	if program.String() != expected {
+		t.Fatalf("expected %s. got=%s", expected, program.String())
+	}
+}
+
+func TestModifyTypes(t *testing.T) {
+	intToFloat := func(node ast.Node) ast.Node {
+		if named, ok := node.(*ast.NamedType); ok && named.Name == "int" {
+			return &ast.NamedType{Token: token.Token{Type: token.IDENT, Literal: "float"}, Name: "float"}
+		}
+
+		return node
+	}
+
+	tests := []string{
+		`let x: int = 1;`,
+		`let xs: [int] = [];`,
+		`let f = fn(a: int, b) -> {string: int} { a };`,
+		`let g: fn(int, [int]) -> int = f;`,
+	}
+
+	for _, input := range tests {
+		expected := parse(t, strings.ReplaceAll(input, "int", "float")).String()
+
+		modified := ast.Modify(parse(t, input), intToFloat)
+		if modified.String() != expected {
+			t.Errorf("%s\n\texpected %s. got=%s", input, expected, modified.String())
+		}
+	}
+}
+
+func TestModifyPanicsOnMismatchedReplacement(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "cannot replace *ast.BlockStatement with *ast.IntegerLiteral") {
+			t.Fatalf("expected a mismatch panic. got=%v", r)
+		}
+	}()
+
+	ast.Modify(parse(t, `fn() { 1 };`), func(node ast.Node) ast.Node {
+		if _, ok := node.(*ast.BlockStatement); ok {
+			return &ast.IntegerLiteral{Value: 1}
+		}
+
+		return node
+	})
+}
@@ -0,0 +1,89 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// TypeExpression is a type annotation, the annotations are optional and
+// only the type checker reads them, the evaluation ignores them
+type TypeExpression interface {
+	Node
+	typeNode()
+}
+
+// NamedType is a type written by its name, eg. `int` or `string`
+type NamedType struct {
+	Token token.Token // the name token
+	Name  string
+}
+
+func (nt *NamedType) typeNode() {}
+func (nt *NamedType) TokenLiteral() string {
+	return nt.Token.Literal
+}
+func (nt *NamedType) Pos() token.Position {
+	return nt.Token.Pos
+}
+func (nt *NamedType) String() string {
+	return nt.Name
+}
+
+// ArrayType is the type of the arrays of elements, eg. `[int]`
+type ArrayType struct {
+	Token   token.Token // the `[` token
+	Element TypeExpression
+}
+
+func (at *ArrayType) typeNode() {}
+func (at *ArrayType) TokenLiteral() string {
+	return at.Token.Literal
+}
+func (at *ArrayType) Pos() token.Position {
+	return at.Token.Pos
+}
+func (at *ArrayType) String() string {
+	return "[" + at.Element.String() + "]"
+}
+
+// HashType is the type of the hashes of keys to values, eg. `{string: int}`
+type HashType struct {
+	Token token.Token // the `{` token
+	Key   TypeExpression
+	Value TypeExpression
+}
+
+func (ht *HashType) typeNode() {}
+func (ht *HashType) TokenLiteral() string {
+	return ht.Token.Literal
+}
+func (ht *HashType) Pos() token.Position {
+	return ht.Token.Pos
+}
+func (ht *HashType) String() string {
+	return "{" + ht.Key.String() + ": " + ht.Value.String() + "}"
+}
+
+// FunctionType is the type of the functions, eg. `fn(int, int) -> bool`
+type FunctionType struct {
+	Token      token.Token // the `fn` token
+	Parameters []TypeExpression
+	Result     TypeExpression
+}
+
+func (ft *FunctionType) typeNode() {}
+func (ft *FunctionType) TokenLiteral() string {
+	return ft.Token.Literal
+}
+func (ft *FunctionType) Pos() token.Position {
+	return ft.Token.Pos
+}
+func (ft *FunctionType) String() string {
+	params := make([]string, len(ft.Parameters))
+	for idx, param := range ft.Parameters {
+		params[idx] = param.String()
+	}
+
+	return "fn(" + strings.Join(params, ", ") + ") -> " + ft.Result.String()
+}
@@ -0,0 +1,168 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil)
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the AST in depth-first order: it starts by calling
+// v.Visit(node), node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w
+// for each of the non-nil children of node, in the order they appear in
+// the source, followed by a call of w.Visit(nil)
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+
+	case *LetStatement:
+		Walk(v, n.Name)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ReturnStatement:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+
+	case *ImportStatement:
+		Walk(v, n.Path)
+		Walk(v, n.Alias)
+
+	case *ThrowStatement:
+		Walk(v, n.Value)
+
+	case *Identifier, *BooleanLiteral, *IntegerLiteral, *FloatLiteral, *StringLiteral:
+		// nothing to do
+
+	case *PrefixExpression:
+		Walk(v, n.Right)
+
+	case *InfixExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+
+	case *IfExpression:
+		Walk(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+
+	case *FunctionLiteral:
+		for idx, param := range n.Parameters {
+			Walk(v, param)
+			if typ := n.ParameterType(idx); typ != nil {
+				Walk(v, typ)
+			}
+		}
+		if n.Result != nil {
+			Walk(v, n.Result)
+		}
+		Walk(v, n.Body)
+
+	case *CallExpression:
+		Walk(v, n.Function)
+		walkExpressions(v, n.Arguments)
+
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			Walk(v, pair.Key)
+			Walk(v, pair.Value)
+		}
+
+	case *IndexExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Index)
+
+	case *SelectorExpression:
+		Walk(v, n.Left)
+		Walk(v, n.Selector)
+
+	case *TryExpression:
+		Walk(v, n.Block)
+		if n.Param != nil {
+			Walk(v, n.Param)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+
+	case *NamedType:
+		// nothing to do
+
+	case *ArrayType:
+		Walk(v, n.Element)
+
+	case *HashType:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+
+	case *FunctionType:
+		for _, param := range n.Parameters {
+			Walk(v, param)
+		}
+		Walk(v, n.Result)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		Walk(v, stmt)
+	}
+}
+
+func walkExpressions(v Visitor, exps []Expression) {
+	for _, exp := range exps {
+		Walk(v, exp)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses the AST in depth-first order: it starts by calling
+// f(node), node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a
+// call of f(nil)
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
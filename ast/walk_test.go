@@ -0,0 +1,121 @@
+package ast_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("unexpected parser errors: %v", p.Errors())
+	}
+
+	return program
+}
+
+func TestInspectVisitsEveryChild(t *testing.T) {
+	const input = `import "lib.al" as lib;
+let add = fn(a, b) { return a + b; };
+if (!true) { add(1, 2.5) } else { [x, "s"][0] };
+{"k": lib.v};
+try { throw -1; } catch (e) { e } finally { 0 }`
+
+	var visited []string
+	ast.Inspect(parse(t, input), func(node ast.Node) bool {
+		if node != nil {
+			name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+			visited = append(visited, name+" "+node.TokenLiteral())
+		}
+		return true
+	})
+
+	expected := []string{
+		"Program import",
+		"ImportStatement import", "StringLiteral lib.al", "Identifier lib",
+		"LetStatement let", "Identifier add",
+		"FunctionLiteral fn", "Identifier a", "Identifier b",
+		"BlockStatement {", "ReturnStatement return",
+		"InfixExpression +", "Identifier a", "Identifier b",
+		"ExpressionStatement if", "IfExpression if",
+		"PrefixExpression !", "BooleanLiteral true",
+		"BlockStatement {", "ExpressionStatement add",
+		"CallExpression (", "Identifier add", "IntegerLiteral 1", "FloatLiteral 2.5",
+		"BlockStatement {", "ExpressionStatement [",
+		"IndexExpression [", "ArrayLiteral [", "Identifier x", "StringLiteral s", "IntegerLiteral 0",
+		"ExpressionStatement {", "HashLiteral {", "StringLiteral k",
+		"SelectorExpression .", "Identifier lib", "Identifier v",
+		"ExpressionStatement try", "TryExpression try",
+		"BlockStatement {", "ThrowStatement throw", "PrefixExpression -", "IntegerLiteral 1",
+		"Identifier e",
+		"BlockStatement {", "ExpressionStatement e", "Identifier e",
+		"BlockStatement {", "ExpressionStatement 0", "IntegerLiteral 0",
+	}
+
+	if strings.Join(visited, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("wrong visit order.\nexpected:\n%s\ngot:\n%s",
+			strings.Join(expected, "\n"), strings.Join(visited, "\n"))
+	}
+}
+
+type depthVisitor struct {
+	depth    int
+	maxDepth *int
+	leaves   *int
+}
+
+func (v depthVisitor) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		*v.leaves++
+		return nil
+	}
+
+	if v.depth > *v.maxDepth {
+		*v.maxDepth = v.depth
+	}
+
+	return depthVisitor{depth: v.depth + 1, maxDepth: v.maxDepth, leaves: v.leaves}
+}
+
+func TestWalkCallsVisitNilAfterChildren(t *testing.T) {
+	var maxDepth, ends int
+	ast.Walk(depthVisitor{maxDepth: &maxDepth, leaves: &ends}, parse(t, `let x = 1 + 2;`))
+
+	// Program > LetStatement > InfixExpression > IntegerLiteral
+	if maxDepth != 3 {
+		t.Fatalf("expected depth 3. got=%d", maxDepth)
+	}
+
+	// one Visit(nil) for each of the 6 nodes
+	if ends != 6 {
+		t.Fatalf("expected 6 calls of Visit(nil). got=%d", ends)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	program := parse(t, `let f = fn(x) { x * 2 }; f(y);`)
+
+	var identifiers []string
+	ast.Inspect(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			identifiers = append(identifiers, ident.Value)
+		}
+
+		_, isFunction := node.(*ast.FunctionLiteral)
+		return !isFunction
+	})
+
+	if got := strings.Join(identifiers, ","); got != "f,f,y" {
+		t.Fatalf("expected the identifiers outside the function f,f,y. got=%s", got)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/types"
+)
+
+// checkCommand prints the type errors in the files
+func checkCommand(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	printTypes := flags.Bool("types", false, "print the types inferred for the top level names")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: alang check [-types] files...\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	code := 0
+	for _, file := range flags.Args() {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+
+		p := parser.New(lexer.New(string(src)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			for _, err := range p.Errors() {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", file, err)
+			}
+			code = 1
+			continue
+		}
+
+		globals, errs := types.Check(program)
+		for _, err := range errs {
+			fmt.Printf("%s:%s\n", file, err)
+			code = 1
+		}
+
+		if *printTypes {
+			names := make([]string, 0, len(globals))
+			for name := range globals {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Printf("%s: %s %s\n", file, name, globals[name])
+			}
+		}
+	}
+
+	return code
+}
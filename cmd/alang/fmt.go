@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/format"
+)
+
+// fmtCommand formats the files, or the standard input when
+// there are none, printing the result to the standard output
+func fmtCommand(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "write the result to the file instead of the standard output")
+	diff := flags.Bool("d", false, "print the diff of the changes instead of the formatted source")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: alang fmt [-w] [-d] [files...]\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "cannot use -w with the standard input")
+			return 2
+		}
+
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		return formatSource("<stdin>", src, false, *diff)
+	}
+
+	code := 0
+	for _, file := range flags.Args() {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+
+		if formatSource(file, src, *write, *diff) != 0 {
+			code = 1
+		}
+	}
+
+	return code
+}
+
+func formatSource(file string, src []byte, write, diff bool) int {
+	formatted, err := format.Source(src)
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, line)
+		}
+		return 1
+	}
+
+	if diff && !bytes.Equal(src, formatted) {
+		fmt.Print(unifiedDiff(file, string(src), string(formatted)))
+	}
+
+	if write && !bytes.Equal(src, formatted) {
+		info, err := os.Stat(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		if err := os.WriteFile(file, formatted, info.Mode().Perm()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	if !write && !diff {
+		os.Stdout.Write(formatted)
+	}
+
+	return 0
+}
+
+// unifiedDiff returns the changes from a to b in the unified
+// format, with three lines of context around each change
+func unifiedDiff(file, a, b string) string {
+	const context = 3
+
+	before := splitLines(a)
+	after := splitLines(b)
+	edits := diffLines(before, after)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s.orig\n+++ %s\n", file, file)
+
+	for start := 0; start < len(edits); {
+		// skip to the next change and take it with its
+		// context, changes close to each other share a hunk
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		from := start - context
+		if from < 0 {
+			from = 0
+		}
+
+		end := start
+		for equal := 0; end < len(edits) && equal <= 2*context; end++ {
+			if edits[end].op == ' ' {
+				equal++
+			} else {
+				equal = 0
+			}
+		}
+
+		to := end
+		for to > start && edits[to-1].op == ' ' {
+			to--
+		}
+		to += context
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		hunk := edits[from:to]
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(hunk[0].a, count(hunk, '-')), hunkRange(hunk[0].b, count(hunk, '+')))
+		for _, edit := range hunk {
+			fmt.Fprintf(&out, "%c%s\n", edit.op, edit.line)
+		}
+
+		start = to
+	}
+
+	return out.String()
+}
+
+// edit is a line kept (' '), removed ('-') or added ('+'), a and b
+// are the indexes of the line before and after the change
+type edit struct {
+	op   byte
+	line string
+	a, b int
+}
+
+// diffLines returns the edits that turn a into b, computed
+// from the longest common subsequence of their lines
+func diffLines(a, b []string) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{op: ' ', line: a[i], a: i, b: j})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{op: '-', line: a[i], a: i, b: j})
+			i++
+		default:
+			edits = append(edits, edit{op: '+', line: b[j], a: i, b: j})
+			j++
+		}
+	}
+
+	return edits
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// count returns how many lines of the hunk are in the side of the
+// diff, the kept lines are in both sides
+func count(hunk []edit, op byte) int {
+	n := 0
+	for _, edit := range hunk {
+		if edit.op == op || edit.op == ' ' {
+			n++
+		}
+	}
+
+	return n
+}
+
+// hunkRange formats the 0-based index of the first line and
+// the count of lines as the 1-based range of a hunk header
+func hunkRange(first, lines int) string {
+	if lines == 0 {
+		return fmt.Sprintf("%d,0", first)
+	}
+
+	return fmt.Sprintf("%d,%d", first+1, lines)
+}
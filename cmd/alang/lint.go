@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/lint"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+// lintCommand prints the issues the linter finds in the files
+func lintCommand(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	disable := flags.String("disable", "", "comma separated IDs of the rules not to check")
+	rules := flags.Bool("rules", false, "print the rules instead of checking files")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: alang lint [-disable rules] [-rules] files...\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if *rules {
+		for _, rule := range lint.Rules {
+			fmt.Printf("%-24s %s\n", rule.ID, rule.Description)
+		}
+		return 0
+	}
+
+	var disabled []string
+	if *disable != "" {
+		disabled = strings.Split(*disable, ",")
+	}
+
+	for _, id := range disabled {
+		if !knownRule(id) {
+			fmt.Fprintf(os.Stderr, "unknown rule: %s\n", id)
+			return 2
+		}
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	code := 0
+	for _, file := range flags.Args() {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			code = 1
+			continue
+		}
+
+		p := parser.New(lexer.New(string(src)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			for _, err := range p.Errors() {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", file, err)
+			}
+			code = 1
+			continue
+		}
+
+		for _, issue := range lint.Lint(program, disabled...) {
+			fmt.Printf("%s:%s\n", file, issue)
+			code = 1
+		}
+	}
+
+	return code
+}
+
+func knownRule(id string) bool {
+	for _, rule := range lint.Rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/repl"
+)
+
+// commands are the subcommands, given as the first argument
+var commands = map[string]func(args []string) int{
+	"parse": parseCommand,
+	"fmt":   fmtCommand,
+	"lint":  lintCommand,
+	"check": checkCommand,
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
+	modulePaths := flag.String("path", os.Getenv("ALANG_PATH"),
+		"list of directories, separated by "+string(os.PathListSeparator)+", where imported modules are searched")
+
+	fileAccess := flag.String("allow", "",
+		"list of directories, separated by "+string(os.PathListSeparator)+", where scripts can read and write files")
+
+	noOptimizations := flag.Bool("noopt", false,
+		"evaluate the programs without folding the constants nor removing the dead branches")
+
+	strict := flag.Bool("strict", false,
+		"do not run the file when it uses names that are not declared")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: alang [-path dirs] [-allow dirs] [-noopt] [-strict] [file.al]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       alang parse [-json] [-tokens] file.al\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       alang fmt [-w] [-d] [files...]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       alang lint [-disable rules] [-rules] files...\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       alang check [-types] files...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var opts []eval.Option
+	if *modulePaths != "" {
+		opts = append(opts, eval.WithModulePaths(filepath.SplitList(*modulePaths)...))
+	}
+
+	if *fileAccess != "" {
+		opts = append(opts, eval.WithFileAccess(filepath.SplitList(*fileAccess)...))
+	}
+
+	if *noOptimizations {
+		opts = append(opts, eval.WithoutOptimizations())
+	}
+
+	if flag.NArg() > 0 {
+		os.Exit(run(flag.Arg(0), *strict, opts))
+	}
+
+	user, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Hello %s! This is the alang!\n", user.Username)
+	fmt.Printf("Feel free to type in commands\n")
+
+	// the REPL imports the modules of the working directory,
+	// as a file imports the ones of its directory
+	opts = append(opts, eval.WithModulePaths("."))
+	repl.Start(os.Stdin, os.Stdout, opts...)
+}
+
+// run evaluates the file and returns the process exit code. The names the
+// resolver finds not declared are reported before running the file, as they
+// fail when the evaluation reaches them, and when strict the file does not run
+func run(file string, strict bool, opts []eval.Option) int {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, err := range p.Errors() {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, err)
+		}
+		return 1
+	}
+
+	evaluator := eval.New(opts...)
+
+	failed := false
+	for _, diagnostic := range evaluator.Prepare(program) {
+		if diagnostic.IsWarning() {
+			continue
+		}
+
+		if strict {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", file, diagnostic)
+			failed = true
+		} else {
+			fmt.Fprintf(os.Stderr, "%s:%s (warning)\n", file, diagnostic)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	evaluated := evaluator.EvalProgram(ctx, file, program, object.NewEnv())
+	if err, ok := evaluated.(*object.Error); ok {
+		fmt.Fprintln(os.Stderr, err.Inspect())
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// parseCommand prints the syntax tree, or the tokens, of a file
+func parseCommand(args []string) int {
+	flags := flag.NewFlagSet("parse", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print as JSON")
+	tokens := flags.Bool("tokens", false, "print the tokens instead of the syntax tree")
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: alang parse [-json] [-tokens] file.al\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	src, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *tokens {
+		return printTokens(os.Stdout, string(src), *asJSON)
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		for _, err := range p.Errors() {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", flags.Arg(0), err)
+		}
+		return 1
+	}
+
+	if !*asJSON {
+		for _, stmt := range program.Statements {
+			fmt.Println(stmt.String())
+		}
+		return 0
+	}
+
+	encoded, err := ast.EncodeJSON(program)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Println(string(encoded))
+	return 0
+}
+
+func printTokens(out io.Writer, src string, asJSON bool) int {
+	l := lexer.New(src)
+
+	tokens := []token.Token{}
+	for tok := l.NextToken(); ; tok = l.NextToken() {
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	if asJSON {
+		if err := json.NewEncoder(out).Encode(tokens); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, tok := range tokens {
+		fmt.Fprintf(out, "%s\t%s\t%q\n", tok.Pos, tok.Type, tok.Literal)
+	}
+
+	return 0
+}
@@ -0,0 +1,16 @@
+package alang
+
+import (
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// ToObject converts a Go value to the language representation, see eval.ToObject
+func ToObject(value interface{}) (object.Representation, error) {
+	return eval.ToObject(value)
+}
+
+// FromObject converts the language representation to a Go value, see eval.FromObject
+func FromObject(rep object.Representation) interface{} {
+	return eval.FromObject(rep)
+}
@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// pseudo types accepted by checkArgs, ANY in place of any
+// representation type and NUMBER for integers and floats
+const (
+	ANY    object.Type = "ANY"
+	NUMBER object.Type = "NUMBER"
+)
+
+// builtins are the functions available to every script, they are
+// looked up after the environment so a script can shadow them
+var builtins = map[string]*object.Builtin{}
+
+// evaluatorBuiltins create builtins that need the state of
+// the Evaluator running them, like the random number generator
+var evaluatorBuiltins = map[string]func(e *Evaluator) object.BuiltinFunction{}
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"is_null": builtinIsNull,
+	})
+}
+
+// registerBuiltins makes the functions available to the scripts by their names
+func registerBuiltins(fns map[string]object.BuiltinFunction) {
+	for name, fn := range fns {
+		builtins[name] = &object.Builtin{Name: name, Fn: fn}
+	}
+}
+
+// registerEvaluatorBuiltins makes the functions available to the scripts by
+// their names, each Evaluator creates its own instances when it is created
+func registerEvaluatorBuiltins(fns map[string]func(e *Evaluator) object.BuiltinFunction) {
+	for name, fn := range fns {
+		evaluatorBuiltins[name] = fn
+	}
+}
+
+// BuiltinNames returns the names of the builtins sorted, eg. to tell
+// the resolver which names are declared before a program runs
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins)+len(evaluatorBuiltins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+
+	for name := range evaluatorBuiltins {
+		if _, shared := builtins[name]; !shared {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// lookupBuiltin returns the builtin bound to the Evaluator or the shared one
+func (e *Evaluator) lookupBuiltin(name string) (*object.Builtin, bool) {
+	if builtin, has := e.builtins[name]; has {
+		return builtin, true
+	}
+
+	builtin, has := builtins[name]
+	return builtin, has
+}
+
+// is_null(v) reports whether v is null, eg. read_line at the end of
+// the input or a missing hash key
+func builtinIsNull(args ...object.Representation) object.Representation {
+	if err := checkArgs("is_null", args, 1, ANY); err != nil {
+		return err
+	}
+
+	return nativeBoolToBoolean(args[0].Type() == object.NULL_OBJ)
+}
+
+// checkArgs validates the arguments given to the builtin name, the first
+// `required` types are mandatory and the remaining ones optional, ANY
+// accepts a representation of any type
+func checkArgs(name string, args []object.Representation, required int, types ...object.Type) *object.Error {
+	if len(args) < required || len(args) > len(types) {
+		expected := fmtArity(required, len(types))
+		return errorF(object.ArityError, "wrong number of arguments to %s: expected %s, got=%d", name, expected, len(args))
+	}
+
+	for idx, arg := range args {
+		if !hasType(arg, types[idx]) {
+			return errorF(object.TypeError, "argument %d to %s must be %s, got=%s", idx+1, name, types[idx], arg.Type())
+		}
+	}
+
+	return nil
+}
+
+func hasType(arg object.Representation, expected object.Type) bool {
+	switch expected {
+	case ANY:
+		return true
+	case NUMBER:
+		return arg.Type() == object.INTEGER_OBJ || arg.Type() == object.FLOAT_OBJ
+	default:
+		return arg.Type() == expected
+	}
+}
+
+func fmtArity(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("%d", min)
+	}
+
+	return fmt.Sprintf("%d to %d", min, max)
+}
@@ -0,0 +1,316 @@
+package eval
+
+import (
+	"sort"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+func init() {
+	registerEvaluatorBuiltins(map[string]func(e *Evaluator) object.BuiltinFunction{
+		"map":     builtinMap,
+		"filter":  builtinFilter,
+		"reduce":  builtinReduce,
+		"each":    builtinEach,
+		"any":     builtinAny,
+		"all":     builtinAll,
+		"sort_by": builtinSortBy,
+		"zip":     builtinZip,
+		"range":   builtinRange,
+	})
+}
+
+// map(arr, f) returns a new array with f applied to each element
+func builtinMap(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("map", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		elements := args[0].(*object.Array).Elements
+		mapped := make([]object.Representation, len(elements))
+		for idx, element := range elements {
+			result := e.ApplyFunction(args[1], element)
+			if isError(result) {
+				return result
+			}
+
+			mapped[idx] = result
+		}
+
+		return &object.Array{Elements: mapped}
+	}
+}
+
+// filter(arr, f) returns a new array with the elements for which f returns true
+func builtinFilter(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("filter", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		filtered := []object.Representation{}
+		for _, element := range args[0].(*object.Array).Elements {
+			keep, err := e.applyPredicate("filter", args[1], element)
+			if err != nil {
+				return err
+			}
+
+			if keep {
+				filtered = append(filtered, element)
+			}
+		}
+
+		return &object.Array{Elements: filtered}
+	}
+}
+
+// reduce(arr, f, initial?) folds the array calling f(accumulator, element),
+// without initial the first element is the initial accumulator
+func builtinReduce(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("reduce", args, 2, object.ARRAY_OBJ, ANY, ANY); err != nil {
+			return err
+		}
+
+		elements := args[0].(*object.Array).Elements
+
+		var accumulator object.Representation
+		if len(args) == 3 {
+			accumulator = args[2]
+		} else {
+			if len(elements) == 0 {
+				return errorF(object.ValueError, "reduce: empty array without initial value")
+			}
+
+			accumulator, elements = elements[0], elements[1:]
+		}
+
+		for _, element := range elements {
+			accumulator = e.ApplyFunction(args[1], accumulator, element)
+			if isError(accumulator) {
+				return accumulator
+			}
+		}
+
+		return accumulator
+	}
+}
+
+// each(arr, f) calls f with each element and returns null
+func builtinEach(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("each", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		for _, element := range args[0].(*object.Array).Elements {
+			if result := e.ApplyFunction(args[1], element); isError(result) {
+				return result
+			}
+		}
+
+		return Null
+	}
+}
+
+// any(arr, f) returns whether f returns true for some element,
+// it stops at the first element for which f returns true
+func builtinAny(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("any", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		for _, element := range args[0].(*object.Array).Elements {
+			ok, err := e.applyPredicate("any", args[1], element)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				return True
+			}
+		}
+
+		return False
+	}
+}
+
+// all(arr, f) returns whether f returns true for every element,
+// it stops at the first element for which f returns false
+func builtinAll(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("all", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		for _, element := range args[0].(*object.Array).Elements {
+			ok, err := e.applyPredicate("all", args[1], element)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				return False
+			}
+		}
+
+		return True
+	}
+}
+
+// sort_by(arr, cmp) returns a new array sorted by the comparator, cmp(a, b)
+// returns a negative number when a comes before b, a positive one when
+// it comes after and zero to keep their order
+func builtinSortBy(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("sort_by", args, 2, object.ARRAY_OBJ, ANY); err != nil {
+			return err
+		}
+
+		sorted := make([]object.Representation, len(args[0].(*object.Array).Elements))
+		copy(sorted, args[0].(*object.Array).Elements)
+
+		// the first error stops calling the comparator,
+		// the remaining comparisons keep the order
+		var failure object.Representation
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if failure != nil {
+				return false
+			}
+
+			result := e.ApplyFunction(args[1], sorted[i], sorted[j])
+			if isError(result) {
+				failure = result
+				return false
+			}
+
+			switch result := result.(type) {
+			case *object.Integer:
+				return result.Value < 0
+			case *object.Float:
+				return result.Value < 0
+			default:
+				failure = errorF(object.TypeError, "sort_by: comparator must return NUMBER, got=%s", result.Type())
+				return false
+			}
+		})
+
+		if failure != nil {
+			return failure
+		}
+
+		return &object.Array{Elements: sorted}
+	}
+}
+
+// zip(arrays...) returns an array with the arrays of the elements at the
+// same index, it is as long as the shortest of the arrays
+func builtinZip(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if len(args) == 0 {
+			return errorF(object.ArityError, "wrong number of arguments to zip: expected at least 1, got=0")
+		}
+
+		shortest := -1
+		for idx, arg := range args {
+			array, ok := arg.(*object.Array)
+			if !ok {
+				return errorF(object.TypeError, "argument %d to zip must be ARRAY, got=%s", idx+1, arg.Type())
+			}
+
+			if shortest < 0 || len(array.Elements) < shortest {
+				shortest = len(array.Elements)
+			}
+		}
+
+		if err := e.allocate(int64(shortest) * (arraySize + int64(len(args))*elementSize)); err != nil {
+			return err
+		}
+
+		zipped := make([]object.Representation, shortest)
+		for idx := range zipped {
+			tuple := make([]object.Representation, len(args))
+			for pos, arg := range args {
+				tuple[pos] = arg.(*object.Array).Elements[idx]
+			}
+
+			zipped[idx] = &object.Array{Elements: tuple}
+		}
+
+		return &object.Array{Elements: zipped}
+	}
+}
+
+// range(end), range(start, end) and range(start, end, step) return the
+// integers from start, 0 by default, up to end exclusive, step is 1 by default
+// and can be negative to count down
+func builtinRange(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("range", args, 1, object.INTEGER_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+
+		start, end, step := int64(0), args[0].(*object.Integer).Value, int64(1)
+		if len(args) > 1 {
+			start, end = end, args[1].(*object.Integer).Value
+		}
+
+		if len(args) > 2 {
+			step = args[2].(*object.Integer).Value
+		}
+
+		if step == 0 {
+			return errorF(object.ValueError, "range: step must not be zero")
+		}
+
+		// the distance between start and end and the size of the step
+		// are computed as unsigned so they do not overflow
+		count := uint64(0)
+		if step > 0 && start < end {
+			count = (uint64(end)-uint64(start)-1)/uint64(step) + 1
+		} else if step < 0 && start > end {
+			count = (uint64(start)-uint64(end)-1)/uint64(-step) + 1
+		}
+
+		// a count that big does not fit anyway, -1 makes reserve reject it
+		size := int64(-1)
+		if count <= maxAllocation {
+			size = arraySize + int64(count)*(elementSize+integerSize)
+		}
+
+		// checks the integers and the array fit the budget before creating them
+		// so a huge range fails with the memory limit error, then accounts the
+		// integers as apply only accounts the array
+		if err := e.reserve("range", size); err != nil {
+			return err
+		}
+
+		if err := e.allocate(int64(count) * integerSize); err != nil {
+			return err
+		}
+
+		elements := make([]object.Representation, count)
+		for idx := range elements {
+			elements[idx] = &object.Integer{Value: start + int64(idx)*step}
+		}
+
+		return &object.Array{Elements: elements}
+	}
+}
+
+// applyPredicate calls f with the element and requires a boolean result
+func (e *Evaluator) applyPredicate(name string, f, element object.Representation) (bool, object.Representation) {
+	result := e.ApplyFunction(f, element)
+	if isError(result) {
+		return false, result
+	}
+
+	boolean, ok := result.(*object.Boolean)
+	if !ok {
+		return false, errorF(object.TypeError, "%s: callback must return BOOLEAN, got=%s", name, result.Type())
+	}
+
+	return boolean.Value, nil
+}
@@ -0,0 +1,42 @@
+package eval
+
+import (
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"error":    builtinError,
+		"is_error": builtinIsError,
+	})
+}
+
+// error(kind, message, cause?) creates an error value that can be thrown,
+// kind is the name of an error kind like "TypeError" or "User" and cause
+// is the error value that led to this one
+func builtinError(args ...object.Representation) object.Representation {
+	if err := checkArgs("error", args, 2, object.STRING_OBJ, object.STRING_OBJ, object.ERROR_VALUE_OBJ); err != nil {
+		return err
+	}
+
+	kind, ok := object.LookupErrorKind(args[0].(*object.String).Value)
+	if !ok {
+		return errorF(object.ValueError, "error: unknown error kind %s", args[0].Inspect())
+	}
+
+	err := &object.Error{Kind: kind, Message: args[1].(*object.String).Value}
+	if len(args) == 3 {
+		err.Cause = args[2].(*object.ErrorValue).Error
+	}
+
+	return &object.ErrorValue{Error: err}
+}
+
+// is_error(v) reports whether v is an error value, eg. the one bound by catch
+func builtinIsError(args ...object.Representation) object.Representation {
+	if err := checkArgs("is_error", args, 1, ANY); err != nil {
+		return err
+	}
+
+	return nativeBoolToBoolean(args[0].Type() == object.ERROR_VALUE_OBJ)
+}
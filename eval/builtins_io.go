@@ -0,0 +1,201 @@
+package eval
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// ErrFileAccessDenied is the Err of the error returned when a script reads or
+// writes a file outside the directories given to WithFileAccess, or imports
+// a module outside the directories allowed by WithModulePaths
+var ErrFileAccessDenied = errors.New("file access denied")
+
+// WithStdout sets where print and println write, os.Stdout by default
+func WithStdout(w io.Writer) Option {
+	return func(e *Evaluator) {
+		e.stdout = w
+	}
+}
+
+// WithStdin sets where read_line reads from, os.Stdin by default
+func WithStdin(r io.Reader) Option {
+	return func(e *Evaluator) {
+		e.stdin = bufio.NewReader(r)
+	}
+}
+
+// WithFileAccess allows read_file and write_file to access the files
+// inside the directories, including their subdirectories. Without this
+// option the scripts cannot access any file
+func WithFileAccess(dirs ...string) Option {
+	return func(e *Evaluator) {
+		for _, dir := range dirs {
+			if resolved, err := resolvePath(dir); err == nil {
+				e.fileAccess = append(e.fileAccess, resolved)
+			}
+		}
+	}
+}
+
+func init() {
+	registerEvaluatorBuiltins(map[string]func(e *Evaluator) object.BuiltinFunction{
+		"print":      builtinPrint,
+		"println":    builtinPrintln,
+		"read_line":  builtinReadLine,
+		"read_file":  builtinReadFile,
+		"write_file": builtinWriteFile,
+	})
+}
+
+// print(values...) writes the values separated by spaces
+func builtinPrint(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		return e.print("print", args, "")
+	}
+}
+
+// println(values...) writes the values separated by spaces and a new line
+func builtinPrintln(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		return e.print("println", args, "\n")
+	}
+}
+
+func (e *Evaluator) print(name string, args []object.Representation, end string) object.Representation {
+	values := make([]string, len(args))
+	for idx, arg := range args {
+		values[idx] = arg.Inspect()
+	}
+
+	if _, err := io.WriteString(e.stdout, strings.Join(values, " ")+end); err != nil {
+		return ioError(name, err)
+	}
+
+	return Null
+}
+
+// read_line() returns the next line without the line break, or null
+// when there is nothing else to read
+func builtinReadLine(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("read_line", args, 0); err != nil {
+			return err
+		}
+
+		line, err := e.stdin.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return Null
+		}
+
+		if err != nil && err != io.EOF {
+			return ioError("read_line", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		return &object.String{Value: strings.TrimSuffix(line, "\r")}
+	}
+}
+
+// read_file(path) returns the content of the file
+func builtinReadFile(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("read_file", args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		path, denied := e.allowFile("read_file", args[0].(*object.String).Value)
+		if denied != nil {
+			return denied
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return ioError("read_file", err)
+		}
+
+		return &object.String{Value: string(content)}
+	}
+}
+
+// write_file(path, content) creates or truncates the file writing the content
+func builtinWriteFile(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("write_file", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		path, denied := e.allowFile("write_file", args[0].(*object.String).Value)
+		if denied != nil {
+			return denied
+		}
+
+		if err := os.WriteFile(path, []byte(args[1].(*object.String).Value), 0o644); err != nil {
+			return ioError("write_file", err)
+		}
+
+		return Null
+	}
+}
+
+// allowFile resolves the path and checks it is inside one of the directories
+// given to WithFileAccess, the symbolic links are followed so a link cannot
+// be used to escape the allowed directories
+func (e *Evaluator) allowFile(name, path string) (string, *object.Error) {
+	if resolved, ok := inside(e.fileAccess, path); ok {
+		return resolved, nil
+	}
+
+	denied := errorF(object.IOError, "%s: access to %s denied", name, path)
+	denied.Err = ErrFileAccessDenied
+	return "", denied
+}
+
+// inside resolves the path and reports whether it is inside
+// one of the directories, which must be already resolved
+func inside(dirs []string, path string) (string, bool) {
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", false
+	}
+
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, true
+		}
+	}
+
+	return "", false
+}
+
+// resolvePath returns the absolute path following the symbolic links,
+// the last element of the path does not need to exist
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+
+	dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
+
+func ioError(name string, err error) *object.Error {
+	ioErr := errorF(object.IOError, "%s: %s", name, err)
+	ioErr.Err = err
+	return ioErr
+}
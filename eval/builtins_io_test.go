@@ -0,0 +1,131 @@
+package eval_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func evalWith(input string, opts ...eval.Option) object.Representation {
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	return eval.New(opts...).Eval(p.ParseProgram(), object.NewEnv())
+}
+
+func TestPrintBuiltins(t *testing.T) {
+	var out strings.Builder
+
+	const input = `print("a", 1, [true]); println(); println("b", 2.5); read_line();`
+	evaluated := evalWith(input,
+		eval.WithStdout(&out),
+		eval.WithStdin(strings.NewReader("")))
+
+	testEvaluatedObject(t, input, evaluated, nil)
+
+	if expected := "a 1 [true]\nb 2.5\n"; out.String() != expected {
+		t.Fatalf("expected output %q. got=%q", expected, out.String())
+	}
+}
+
+func TestReadLineBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`read_line();`, "first"},
+		{`read_line(); read_line();`, "second"},
+		{`read_line(); read_line(); read_line();`, "last"},
+		{`read_line(); read_line(); read_line(); read_line();`, nil},
+		{`read_line(1);`, &object.Error{Message: "wrong number of arguments to read_line: expected 0, got=1"}},
+	}
+
+	for _, tt := range tests {
+		stdin := strings.NewReader("first\nsecond\r\nlast")
+		testEvaluatedObject(t, tt.input, evalWith(tt.input, eval.WithStdin(stdin)), tt.expected)
+	}
+}
+
+func TestReadLinesUntilEnd(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let lines = fn(acc) {
+			let line = read_line();
+			if (is_null(line)) { return acc; }
+			lines(acc + line + ";")
+		};
+		lines("");`, "first;second;last;"},
+		{`let end = {}["missing"];
+		let count = fn(n) {
+			if (read_line() == end) { n } else { count(n + 1) }
+		};
+		count(0);`, 3},
+		{`is_null(1);`, false},
+		{`is_null();`, &object.Error{Message: "wrong number of arguments to is_null: expected 1, got=0"}},
+	}
+
+	for _, tt := range tests {
+		stdin := strings.NewReader("first\nsecond\r\nlast")
+		testEvaluatedObject(t, tt.input, evalWith(tt.input, eval.WithStdin(stdin)), tt.expected)
+	}
+}
+
+func TestFileBuiltins(t *testing.T) {
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed")
+	if err := os.Mkdir(allowed, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink(filepath.Join(dir, "secret.txt"), filepath.Join(allowed, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	quote := func(path string) string {
+		return `"` + filepath.ToSlash(path) + `"`
+	}
+
+	inAllowed := quote(filepath.Join(allowed, "notes.txt"))
+	outside := quote(filepath.Join(dir, "secret.txt"))
+	escaping := quote(filepath.Join(allowed, "..", "secret.txt"))
+	link := quote(filepath.Join(allowed, "link.txt"))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`write_file(` + inAllowed + `, "olá"); read_file(` + inAllowed + `);`, "olá"},
+		{`read_file(` + outside + `);`, &object.Error{Message: "read_file: access to " + filepath.ToSlash(filepath.Join(dir, "secret.txt")) + " denied"}},
+		{`read_file(` + escaping + `);`, &object.Error{Message: "read_file: access to " + filepath.ToSlash(filepath.Join(allowed, "..", "secret.txt")) + " denied"}},
+		{`read_file(` + link + `);`, &object.Error{Message: "read_file: access to " + filepath.ToSlash(filepath.Join(allowed, "link.txt")) + " denied"}},
+		{`write_file(` + outside + `, "x");`, &object.Error{Message: "write_file: access to " + filepath.ToSlash(filepath.Join(dir, "secret.txt")) + " denied"}},
+		{`write_file(` + inAllowed + `);`, &object.Error{Message: "wrong number of arguments to write_file: expected 2, got=1"}},
+	}
+
+	for _, tt := range tests {
+		testEvaluatedObject(t, tt.input, evalWith(tt.input, eval.WithFileAccess(allowed)), tt.expected)
+	}
+
+	denied := evalWith(`read_file(` + inAllowed + `);`)
+	if err, ok := denied.(*object.Error); !ok || !errors.Is(err, eval.ErrFileAccessDenied) {
+		t.Fatalf("expected file access to be denied by default. got=%s", denied.Inspect())
+	}
+
+	missing := evalWith(`read_file(`+quote(filepath.Join(allowed, "missing.txt"))+`);`, eval.WithFileAccess(allowed))
+	if err, ok := missing.(*object.Error); !ok || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a not exist error. got=%s", missing.Inspect())
+	}
+}
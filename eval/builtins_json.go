@@ -0,0 +1,250 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"json_parse":     builtinJSONParse,
+		"json_stringify": builtinJSONStringify,
+	})
+}
+
+// json_parse(str) decodes a JSON document, objects become hashes keeping
+// the order of their keys, numbers without fraction or exponent that fit an
+// INTEGER become integers and the other numbers floats, and null becomes Null
+func builtinJSONParse(args ...object.Representation) object.Representation {
+	if err := checkArgs("json_parse", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(args[0].(*object.String).Value))
+	decoder.UseNumber()
+
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		return errorF(object.ValueError, "json_parse: %s", err)
+	}
+
+	end := decoder.InputOffset()
+	if _, err := decoder.Token(); err != io.EOF {
+		return errorF(object.ValueError, "json_parse: unexpected data after the document at offset %d", end)
+	}
+
+	return value
+}
+
+// decodeJSON reads the next value from the decoder token by token,
+// since decoding into a map would lose the order of the object keys
+func decodeJSON(decoder *json.Decoder) (object.Representation, error) {
+	tok, err := decoder.Token()
+	if err == io.EOF {
+		return nil, errors.New("unexpected end of the document")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case nil:
+		return Null, nil
+	case bool:
+		return nativeBoolToBoolean(tok), nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		return decodeJSONNumber(tok)
+	case json.Delim:
+		switch tok {
+		case '[':
+			array := &object.Array{Elements: []object.Representation{}}
+			for decoder.More() {
+				element, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+
+				array.Elements = append(array.Elements, element)
+			}
+
+			// consumes the closing `]`
+			_, err := decoder.Token()
+			return array, err
+
+		case '{':
+			hash := object.NewHash()
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := decodeJSON(decoder)
+				if err != nil {
+					return nil, err
+				}
+
+				hash.Set(&object.String{Value: key.(string)}, value)
+			}
+
+			// consumes the closing `}`
+			_, err := decoder.Token()
+			return hash, err
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+func decodeJSONNumber(number json.Number) (object.Representation, error) {
+	literal := number.String()
+
+	if !strings.ContainsAny(literal, ".eE") {
+		if integer, err := strconv.ParseInt(literal, 10, 64); err == nil {
+			return &object.Integer{Value: integer}, nil
+		}
+	}
+
+	float, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return nil, fmt.Errorf("number %s out of range", literal)
+	}
+
+	return &object.Float{Value: float}, nil
+}
+
+// json_stringify(value, indent?) encodes the value as JSON, indent is the number
+// of spaces or the string used to indent nested values. Hash keys that are not
+// strings are written as their inspected value. Functions, host values and cyclic
+// structures cannot be encoded
+func builtinJSONStringify(args ...object.Representation) object.Representation {
+	if err := checkArgs("json_stringify", args, 1, ANY, ANY); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := encodeJSON(&out, args[0], map[object.Representation]bool{}); err != nil {
+		return errorF(object.ValueError, "json_stringify: %s", err)
+	}
+
+	if len(args) == 1 {
+		return &object.String{Value: out.String()}
+	}
+
+	var indent string
+	switch arg := args[1].(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return errorF(object.ValueError, "json_stringify: negative indent %d", arg.Value)
+		}
+
+		indent = strings.Repeat(" ", int(arg.Value))
+	case *object.String:
+		indent = arg.Value
+	default:
+		return errorF(object.TypeError, "argument 2 to json_stringify must be INTEGER or STRING, got=%s", arg.Type())
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out.Bytes(), "", indent); err != nil {
+		return errorF(object.ValueError, "json_stringify: %s", err)
+	}
+
+	return &object.String{Value: indented.String()}
+}
+
+// encodeJSON writes the value, visiting holds the arrays and hashes
+// being encoded to find the ones that contain themselves
+func encodeJSON(out *bytes.Buffer, value object.Representation, visiting map[object.Representation]bool) error {
+	switch value := value.(type) {
+	case *object.Null:
+		out.WriteString("null")
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(value.Value))
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(value.Value, 10))
+	case *object.Float:
+		if math.IsNaN(value.Value) || math.IsInf(value.Value, 0) {
+			return fmt.Errorf("cannot encode %s", value.Inspect())
+		}
+
+		// keeps the `.0` of integral values so they decode back to floats
+		out.WriteString(value.Inspect())
+	case *object.String:
+		return encodeJSONString(out, value.Value)
+
+	case *object.Array:
+		if visiting[value] {
+			return errors.New("cyclic structure")
+		}
+
+		visiting[value] = true
+		defer delete(visiting, value)
+
+		out.WriteByte('[')
+		for idx, element := range value.Elements {
+			if idx > 0 {
+				out.WriteByte(',')
+			}
+
+			if err := encodeJSON(out, element, visiting); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+
+	case *object.Hash:
+		if visiting[value] {
+			return errors.New("cyclic structure")
+		}
+
+		visiting[value] = true
+		defer delete(visiting, value)
+
+		out.WriteByte('{')
+		for idx, pair := range value.Ordered() {
+			if idx > 0 {
+				out.WriteByte(',')
+			}
+
+			if err := encodeJSONString(out, pair.Key.Inspect()); err != nil {
+				return err
+			}
+
+			out.WriteByte(':')
+			if err := encodeJSON(out, pair.Value, visiting); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+
+	default:
+		return fmt.Errorf("cannot encode %s", value.Type())
+	}
+
+	return nil
+}
+
+func encodeJSONString(out *bytes.Buffer, value string) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+
+	// Encode terminates every value with a new line
+	out.Truncate(out.Len() - 1)
+	return nil
+}
@@ -0,0 +1,336 @@
+package eval
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// WithRandSeed seeds the random number generator used by rand and
+// rand_int, so scripts replayed with the same seed get the same numbers.
+// Without it the generator is seeded with the current time
+func WithRandSeed(seed int64) Option {
+	return func(e *Evaluator) {
+		e.rand = rand.New(rand.NewSource(seed))
+	}
+}
+
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"abs":   builtinAbs,
+		"min":   minMax("min", func(a, b float64) bool { return a < b }),
+		"max":   minMax("max", func(a, b float64) bool { return a > b }),
+		"pow":   builtinPow,
+		"sqrt":  builtinSqrt,
+		"floor": rounding("floor", math.Floor),
+		"ceil":  rounding("ceil", math.Ceil),
+		"round": rounding("round", math.Round),
+		"clamp": builtinClamp,
+		"gcd":   builtinGcd,
+		"sin":   floatToFloat("sin", math.Sin, nil),
+		"cos":   floatToFloat("cos", math.Cos, nil),
+		"tan":   floatToFloat("tan", math.Tan, nil),
+		"asin":  floatToFloat("asin", math.Asin, unitInterval),
+		"acos":  floatToFloat("acos", math.Acos, unitInterval),
+		"atan":  floatToFloat("atan", math.Atan, nil),
+		"atan2": builtinAtan2,
+	})
+
+	registerEvaluatorBuiltins(map[string]func(e *Evaluator) object.BuiltinFunction{
+		"rand":     builtinRand,
+		"rand_int": builtinRandInt,
+		"seed":     builtinSeed,
+	})
+}
+
+// toFloat returns the value of an integer or float as a float64
+func toFloat(rep object.Representation) (float64, bool) {
+	switch rep := rep.(type) {
+	case *object.Integer:
+		return float64(rep.Value), true
+	case *object.Float:
+		return rep.Value, true
+	default:
+		return 0, false
+	}
+}
+
+// domainCheck returns an error message when x is outside the domain of the function
+type domainCheck func(x float64) string
+
+func unitInterval(x float64) string {
+	if x < -1 || x > 1 {
+		return "argument must be between -1 and 1"
+	}
+
+	return ""
+}
+
+func floatToFloat(name string, fn func(float64) float64, domain domainCheck) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs(name, args, 1, NUMBER); err != nil {
+			return err
+		}
+
+		x, _ := toFloat(args[0])
+		if domain != nil {
+			if msg := domain(x); msg != "" {
+				return errorF(object.ValueError, "%s: domain error, %s, got=%s", name, msg, args[0].Inspect())
+			}
+		}
+
+		return &object.Float{Value: fn(x)}
+	}
+}
+
+// abs(x) keeps the type of x
+func builtinAbs(args ...object.Representation) object.Representation {
+	if err := checkArgs("abs", args, 1, NUMBER); err != nil {
+		return err
+	}
+
+	switch x := args[0].(type) {
+	case *object.Integer:
+		if x.Value == math.MinInt64 {
+			return errorF(object.ValueError, "abs: %d overflows INTEGER", x.Value)
+		}
+
+		if x.Value < 0 {
+			return &object.Integer{Value: -x.Value}
+		}
+
+		return x
+	default:
+		return &object.Float{Value: math.Abs(x.(*object.Float).Value)}
+	}
+}
+
+// min(values...) and max(values...) return one of the given values,
+// keeping its type, the first wins when there are equal values
+func minMax(name string, better func(a, b float64) bool) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if len(args) == 0 {
+			return errorF(object.ArityError, "wrong number of arguments to %s: expected at least 1, got=0", name)
+		}
+
+		var best object.Representation
+		var bestValue float64
+
+		for idx, arg := range args {
+			value, ok := toFloat(arg)
+			if !ok {
+				return errorF(object.TypeError, "argument %d to %s must be %s, got=%s", idx+1, name, NUMBER, arg.Type())
+			}
+
+			if best == nil || better(value, bestValue) {
+				best, bestValue = arg, value
+			}
+		}
+
+		return best
+	}
+}
+
+// pow(base, exp) is an integer when both are integers and exp is not
+// negative, otherwise it is a float
+func builtinPow(args ...object.Representation) object.Representation {
+	if err := checkArgs("pow", args, 2, NUMBER, NUMBER); err != nil {
+		return err
+	}
+
+	base, isIntBase := args[0].(*object.Integer)
+	exp, isIntExp := args[1].(*object.Integer)
+
+	if isIntBase && isIntExp && exp.Value >= 0 {
+		result, ok := intPow(base.Value, exp.Value)
+		if !ok {
+			return errorF(object.ValueError, "pow: %d ** %d overflows INTEGER", base.Value, exp.Value)
+		}
+
+		return &object.Integer{Value: result}
+	}
+
+	x, _ := toFloat(args[0])
+	y, _ := toFloat(args[1])
+
+	if x == 0 && y < 0 {
+		return errorF(object.ValueError, "pow: domain error, zero to a negative power")
+	}
+
+	result := math.Pow(x, y)
+	if math.IsNaN(result) {
+		return errorF(object.ValueError, "pow: domain error, negative base %s to a fractional power %s",
+			args[0].Inspect(), args[1].Inspect())
+	}
+
+	return &object.Float{Value: result}
+}
+
+// intPow raises base to exp by squaring, ok is false when the result
+// overflows. A square is only taken when a bigger power is still needed
+// so it overflows only if the result does
+func intPow(base, exp int64) (result int64, ok bool) {
+	result = 1
+	for exp > 0 {
+		if exp&1 == 1 {
+			if result, ok = mulInt(result, base); !ok {
+				return 0, false
+			}
+		}
+
+		exp >>= 1
+		if exp > 0 {
+			if base, ok = mulInt(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+
+	return result, true
+}
+
+// mulInt multiplies the integers, ok is false when the product overflows
+func mulInt(a, b int64) (product int64, ok bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	product = a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
+
+func builtinSqrt(args ...object.Representation) object.Representation {
+	if err := checkArgs("sqrt", args, 1, NUMBER); err != nil {
+		return err
+	}
+
+	x, _ := toFloat(args[0])
+	if x < 0 {
+		return errorF(object.ValueError, "sqrt: domain error, negative argument %s", args[0].Inspect())
+	}
+
+	return &object.Float{Value: math.Sqrt(x)}
+}
+
+// rounding builtins return integers, a float that does not fit is an error
+func rounding(name string, fn func(float64) float64) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs(name, args, 1, NUMBER); err != nil {
+			return err
+		}
+
+		if integer, ok := args[0].(*object.Integer); ok {
+			return integer
+		}
+
+		rounded := fn(args[0].(*object.Float).Value)
+		if math.IsNaN(rounded) || rounded < math.MinInt64 || rounded >= math.MaxInt64 {
+			return errorF(object.ValueError, "%s: %s overflows INTEGER", name, args[0].Inspect())
+		}
+
+		return &object.Integer{Value: int64(rounded)}
+	}
+}
+
+// clamp(x, low, high) limits x to the interval [low, high]
+func builtinClamp(args ...object.Representation) object.Representation {
+	if err := checkArgs("clamp", args, 3, NUMBER, NUMBER, NUMBER); err != nil {
+		return err
+	}
+
+	x, _ := toFloat(args[0])
+	low, _ := toFloat(args[1])
+	high, _ := toFloat(args[2])
+
+	if low > high {
+		return errorF(object.ValueError, "clamp: low %s is greater than high %s", args[1].Inspect(), args[2].Inspect())
+	}
+
+	switch {
+	case x < low:
+		return args[1]
+	case x > high:
+		return args[2]
+	default:
+		return args[0]
+	}
+}
+
+// gcd(a, b) is the greatest common divisor of two integers, always positive
+func builtinGcd(args ...object.Representation) object.Representation {
+	if err := checkArgs("gcd", args, 2, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+
+	a, b := args[0].(*object.Integer).Value, args[1].(*object.Integer).Value
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	// -a overflows, the gcd is 2 ** 63
+	if a == math.MinInt64 {
+		return errorF(object.ValueError, "gcd: %d and %d have a gcd that overflows INTEGER",
+			args[0].(*object.Integer).Value, args[1].(*object.Integer).Value)
+	}
+
+	if a < 0 {
+		a = -a
+	}
+
+	return &object.Integer{Value: a}
+}
+
+func builtinAtan2(args ...object.Representation) object.Representation {
+	if err := checkArgs("atan2", args, 2, NUMBER, NUMBER); err != nil {
+		return err
+	}
+
+	y, _ := toFloat(args[0])
+	x, _ := toFloat(args[1])
+
+	return &object.Float{Value: math.Atan2(y, x)}
+}
+
+// rand() returns a float in [0, 1)
+func builtinRand(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("rand", args, 0); err != nil {
+			return err
+		}
+
+		return &object.Float{Value: e.rand.Float64()}
+	}
+}
+
+// rand_int(n) returns an integer in [0, n)
+func builtinRandInt(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("rand_int", args, 1, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+
+		n := args[0].(*object.Integer).Value
+		if n <= 0 {
+			return errorF(object.ValueError, "rand_int: n must be positive, got=%d", n)
+		}
+
+		return &object.Integer{Value: e.rand.Int63n(n)}
+	}
+}
+
+// seed(n) seeds the random number generator of the running script
+func builtinSeed(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("seed", args, 1, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+
+		e.rand.Seed(args[0].(*object.Integer).Value)
+		return Null
+	}
+}
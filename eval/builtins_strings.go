@@ -0,0 +1,251 @@
+package eval
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// the positions used by index_of and substr count characters
+// (runes) and not bytes, matching the elements returned by chars
+func init() {
+	registerBuiltins(map[string]object.BuiltinFunction{
+		"split":       builtinSplit,
+		"trim":        stringToString("trim", strings.TrimSpace),
+		"upper":       stringToString("upper", strings.ToUpper),
+		"lower":       stringToString("lower", strings.ToLower),
+		"contains":    stringsToBoolean("contains", strings.Contains),
+		"starts_with": stringsToBoolean("starts_with", strings.HasPrefix),
+		"ends_with":   stringsToBoolean("ends_with", strings.HasSuffix),
+		"index_of":    builtinIndexOf,
+		"substr":      builtinSubstr,
+		"chars":       builtinChars,
+	})
+
+	registerEvaluatorBuiltins(map[string]func(e *Evaluator) object.BuiltinFunction{
+		"repeat":  builtinRepeat,
+		"replace": builtinReplace,
+		"join":    builtinJoin,
+		"format":  builtinFormat,
+	})
+}
+
+func stringToString(name string, fn func(string) string) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs(name, args, 1, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		return &object.String{Value: fn(args[0].(*object.String).Value)}
+	}
+}
+
+func stringsToBoolean(name string, fn func(string, string) bool) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs(name, args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		return nativeBoolToBoolean(fn(args[0].(*object.String).Value, args[1].(*object.String).Value))
+	}
+}
+
+func stringsToArray(values []string) *object.Array {
+	elements := make([]object.Representation, len(values))
+	for idx, value := range values {
+		elements[idx] = &object.String{Value: value}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// split(s, sep) splits s around each sep, an empty sep splits every character
+func builtinSplit(args ...object.Representation) object.Representation {
+	if err := checkArgs("split", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	return stringsToArray(strings.Split(args[0].(*object.String).Value, args[1].(*object.String).Value))
+}
+
+// join(array, sep) concatenates the strings of the array placing sep between them
+func builtinJoin(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("join", args, 2, object.ARRAY_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		elements, sep := args[0].(*object.Array).Elements, args[1].(*object.String).Value
+		values := make([]string, len(elements))
+		size := int64(stringSize)
+
+		for idx, element := range elements {
+			str, ok := element.(*object.String)
+			if !ok {
+				return errorF(object.TypeError, "join: element %d must be STRING, got=%s", idx, element.Type())
+			}
+
+			values[idx] = str.Value
+			size = add(size, int64(len(str.Value)))
+		}
+
+		if len(values) > 1 {
+			size = add(size, multiply(int64(len(values)-1), int64(len(sep))))
+		}
+
+		if err := e.reserve("join", size); err != nil {
+			return err
+		}
+
+		return &object.String{Value: strings.Join(values, sep)}
+	}
+}
+
+// replace(s, old, new) replaces all the occurrences of old by new
+func builtinReplace(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("replace", args, 3, object.STRING_OBJ, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+			return err
+		}
+
+		s, old, replacement := args[0].(*object.String).Value, args[1].(*object.String).Value, args[2].(*object.String).Value
+
+		// an empty old matches before every character and at the end
+		count := int64(utf8.RuneCountInString(s) + 1)
+		if old != "" {
+			count = int64(strings.Count(s, old))
+		}
+
+		size := add(stringSize, int64(len(s)))
+		if len(replacement) > len(old) {
+			size = add(size, multiply(count, int64(len(replacement)-len(old))))
+		}
+
+		if err := e.reserve("replace", size); err != nil {
+			return err
+		}
+
+		return &object.String{Value: strings.ReplaceAll(s, old, replacement)}
+	}
+}
+
+// index_of(s, sub) returns the position of the first sub in s or -1
+func builtinIndexOf(args ...object.Representation) object.Representation {
+	if err := checkArgs("index_of", args, 2, object.STRING_OBJ, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	s, sub := args[0].(*object.String).Value, args[1].(*object.String).Value
+
+	idx := strings.Index(s, sub)
+	if idx < 0 {
+		return &object.Integer{Value: -1}
+	}
+
+	return &object.Integer{Value: int64(len([]rune(s[:idx])))}
+}
+
+// substr(s, start, end?) returns the characters from start up to, but
+// not including, end. Without end it goes until the end of the string
+func builtinSubstr(args ...object.Representation) object.Representation {
+	if err := checkArgs("substr", args, 2, object.STRING_OBJ, object.INTEGER_OBJ, object.INTEGER_OBJ); err != nil {
+		return err
+	}
+
+	runes := []rune(args[0].(*object.String).Value)
+	start, end := args[1].(*object.Integer).Value, int64(len(runes))
+
+	if len(args) == 3 {
+		end = args[2].(*object.Integer).Value
+	}
+
+	if start < 0 || end > int64(len(runes)) || start > end {
+		return errorF(object.IndexError, "substr: range [%d:%d] out of bounds for length %d", start, end, len(runes))
+	}
+
+	return &object.String{Value: string(runes[start:end])}
+}
+
+// repeat(s, n) returns s repeated n times, the size of the result
+// is checked against the memory limit before it is created
+func builtinRepeat(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if err := checkArgs("repeat", args, 2, object.STRING_OBJ, object.INTEGER_OBJ); err != nil {
+			return err
+		}
+
+		s, count := args[0].(*object.String).Value, args[1].(*object.Integer).Value
+		if count < 0 {
+			return errorF(object.ValueError, "repeat: negative count %d", count)
+		}
+
+		size := multiply(int64(len(s)), count)
+		if size >= 0 {
+			size += stringSize
+		}
+
+		if err := e.reserve("repeat", size); err != nil {
+			return err
+		}
+
+		return &object.String{Value: strings.Repeat(s, int(count))}
+	}
+}
+
+// chars(s) returns the characters of s as an array of strings
+func builtinChars(args ...object.Representation) object.Representation {
+	if err := checkArgs("chars", args, 1, object.STRING_OBJ); err != nil {
+		return err
+	}
+
+	runes := []rune(args[0].(*object.String).Value)
+	elements := make([]object.Representation, len(runes))
+	for idx, r := range runes {
+		elements[idx] = &object.String{Value: string(r)}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// format(template, args...) replaces each `{}` of the template by the next
+// argument, strings are inserted as is and other values as they are inspected
+func builtinFormat(e *Evaluator) object.BuiltinFunction {
+	return func(args ...object.Representation) object.Representation {
+		if len(args) == 0 {
+			return errorF(object.ArityError, "wrong number of arguments to format: expected at least 1, got=0")
+		}
+
+		template, ok := args[0].(*object.String)
+		if !ok {
+			return errorF(object.TypeError, "argument 1 to format must be %s, got=%s", object.STRING_OBJ, args[0].Type())
+		}
+
+		parts := strings.Split(template.Value, "{}")
+		if len(parts)-1 != len(args)-1 {
+			return errorF(object.ValueError, "format: template has %d placeholders, got=%d values", len(parts)-1, len(args)-1)
+		}
+
+		size := add(stringSize, int64(len(template.Value)-2*(len(parts)-1)))
+		values := make([]string, len(args)-1)
+
+		for idx, value := range args[1:] {
+			values[idx] = value.Inspect()
+			size = add(size, int64(len(values[idx])))
+		}
+
+		if err := e.reserve("format", size); err != nil {
+			return err
+		}
+
+		var out strings.Builder
+		out.WriteString(parts[0])
+
+		for idx, value := range values {
+			out.WriteString(value)
+			out.WriteString(parts[idx+1])
+		}
+
+		return &object.String{Value: out.String()}
+	}
+}
@@ -0,0 +1,242 @@
+package eval_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`join(split("a,b,c", ","), "-");`, "a-b-c"},
+		{`split("abc", "")[2];`, "c"},
+		{`trim("  alang \n");`, "alang"},
+		{`upper("alang");`, "ALANG"},
+		{`lower("ALang");`, "alang"},
+		{`contains("alang", "lan");`, true},
+		{`contains("alang", "x");`, false},
+		{`starts_with("alang", "al");`, true},
+		{`ends_with("alang", "al");`, false},
+		{`replace("a-b-c", "-", "+");`, "a+b+c"},
+		{`index_of("olá mundo", "mundo");`, 4},
+		{`index_of("alang", "x");`, -1},
+		{`substr("olá mundo", 4);`, "mundo"},
+		{`substr("alang", 1, 3);`, "la"},
+		{`repeat("ab", 3);`, "ababab"},
+		{`chars("olá")[2];`, "á"},
+		{`format("{} + {} = {}", 1, 2, 1 + 2);`, "1 + 2 = 3"},
+		{`format("hello {}!", "alang");`, "hello alang!"},
+		{`let upper = fn(s) { s }; upper("shadowed");`, "shadowed"},
+
+		{`upper(1);`, &object.Error{Message: "argument 1 to upper must be STRING, got=INTEGER"}},
+		{`split("a");`, &object.Error{Message: "wrong number of arguments to split: expected 2, got=1"}},
+		{`substr("a", 0, 1, 2);`, &object.Error{Message: "wrong number of arguments to substr: expected 2 to 3, got=4"}},
+		{`substr("alang", 3, 10);`, &object.Error{Message: "substr: range [3:10] out of bounds for length 5"}},
+		{`join([1], ",");`, &object.Error{Message: "join: element 0 must be STRING, got=INTEGER"}},
+		{`repeat("a", -1);`, &object.Error{Message: "repeat: negative count -1"}},
+		{`repeat("ab", 4611686018427387904);`, &object.Error{Message: "repeat: result too large"}},
+		{`replace(repeat("a", 2000000), "", repeat("b", 1000000));`, &object.Error{Message: "replace: result too large"}},
+		{`format("{} {}", 1);`, &object.Error{Message: "format: template has 2 placeholders, got=1 values"}},
+		{`format();`, &object.Error{Message: "wrong number of arguments to format: expected at least 1, got=0"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`abs(-5);`, 5},
+		{`abs(-2.5);`, 2.5},
+		{`min(3, 1.5, 2);`, 1.5},
+		{`max(3, 1.5, 2);`, 3},
+		{`pow(2, 10);`, 1024},
+		{`pow(1, 9223372036854775807);`, 1},
+		{`pow(-1, 9223372036854775807);`, -1},
+		{`pow(-2, 63);`, -9223372036854775807 - 1},
+		{`pow(3, 39);`, 4052555153018976267},
+		{`pow(2, -1);`, 0.5},
+		{`pow(4, 0.5);`, 2.0},
+		{`sqrt(16);`, 4.0},
+		{`floor(2.7);`, 2},
+		{`ceil(2.1);`, 3},
+		{`round(2.5);`, 3},
+		{`round(-2.5);`, -3},
+		{`floor(7);`, 7},
+		{`clamp(15, 0, 10);`, 10},
+		{`clamp(-1, 0, 10);`, 0},
+		{`clamp(5.5, 0, 10);`, 5.5},
+		{`gcd(12, 18);`, 6},
+		{`gcd(-4, 6);`, 2},
+		{`sin(0);`, 0.0},
+		{`cos(0);`, 1.0},
+		{`atan2(1, 1) * 4;`, 3.141592653589793},
+		{`asin(1) * 2;`, 3.141592653589793},
+
+		{`sqrt(-1);`, &object.Error{Message: "sqrt: domain error, negative argument -1"}},
+		{`acos(2);`, &object.Error{Message: "acos: domain error, argument must be between -1 and 1, got=2"}},
+		{`pow(-8, 0.5);`, &object.Error{Message: "pow: domain error, negative base -8 to a fractional power 0.5"}},
+		{`pow(0, -1);`, &object.Error{Message: "pow: domain error, zero to a negative power"}},
+		{`pow(10, 19);`, &object.Error{Message: "pow: 10 ** 19 overflows INTEGER"}},
+		{`pow(2, 64);`, &object.Error{Message: "pow: 2 ** 64 overflows INTEGER"}},
+		{`gcd(-9223372036854775807 - 1, 0);`, &object.Error{
+			Message: "gcd: -9223372036854775808 and 0 have a gcd that overflows INTEGER",
+		}},
+		{`abs("a");`, &object.Error{Message: "argument 1 to abs must be NUMBER, got=STRING"}},
+		{`min();`, &object.Error{Message: "wrong number of arguments to min: expected at least 1, got=0"}},
+		{`clamp(1, 10, 0);`, &object.Error{Message: "clamp: low 10 is greater than high 0"}},
+		{`gcd(1.5, 2);`, &object.Error{Message: "argument 1 to gcd must be INTEGER, got=FLOAT"}},
+		{`rand_int(0);`, &object.Error{Message: "rand_int: n must be positive, got=0"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestRandIsDeterministicWithSeed(t *testing.T) {
+	const input = `[rand_int(1000), rand_int(1000), rand()];`
+
+	run := func(opts ...eval.Option) string {
+		l := lexer.New(input)
+		p := parser.New(l)
+
+		return eval.New(opts...).Eval(p.ParseProgram(), object.NewEnv()).Inspect()
+	}
+
+	first := run(eval.WithRandSeed(42))
+	if second := run(eval.WithRandSeed(42)); first != second {
+		t.Fatalf("expected the same numbers with the same seed. got=%s and %s", first, second)
+	}
+
+	const seeded = `seed(7); let a = rand_int(1000); seed(7); a == rand_int(1000);`
+	testEvaluatedObject(t, seeded, testEval(seeded), true)
+}
+
+func TestJSONBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`json_parse("{\"b\": [1, 2.5, true], \"a\": null}")["b"][1];`, 2.5},
+		{`json_parse("null");`, nil},
+		{`json_parse("null") == json_parse("{\"a\": null}")["a"];`, true},
+		{`json_parse("[null, 0]")[0] == json_parse("[null, 0]")[1];`, false},
+		{`json_parse("\"\"") != json_parse("null");`, true},
+		{`json_parse("9007199254740993");`, 9007199254740993},
+		{`json_parse("\"ol\\u00e1\"");`, "olá"},
+		{`json_stringify(json_parse("{\"b\": [1, 2.0, 0.1], \"a\": null}"));`, `{"b":[1,2.0,0.1],"a":null}`},
+		{`json_stringify({"a": [1, "<x>"], 1: true}, 2);`, "{\n  \"a\": [\n    1,\n    \"<x>\"\n  ],\n  \"1\": true\n}"},
+		{`json_stringify("tab\t");`, `"tab\t"`},
+		{`json_parse(json_stringify(0.1 + 0.2)) == 0.1 + 0.2;`, true},
+
+		{`json_parse("");`, &object.Error{Message: "json_parse: unexpected end of the document"}},
+		{`json_parse("{");`, &object.Error{Message: "json_parse: unexpected end of JSON input"}},
+		{`json_parse("[1] 2");`, &object.Error{Message: "json_parse: unexpected data after the document at offset 3"}},
+		{`json_parse("1e400");`, &object.Error{Message: "json_parse: number 1e400 out of range"}},
+		{`json_stringify(fn(x) { x });`, &object.Error{Message: "json_stringify: cannot encode FUNCTION_OBJ"}},
+		{`json_stringify([upper]);`, &object.Error{Message: "json_stringify: cannot encode BUILTIN"}},
+		{`json_stringify(1, []);`, &object.Error{Message: "argument 2 to json_stringify must be INTEGER or STRING, got=ARRAY"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestJSONStringifyRejectsCycles(t *testing.T) {
+	array := &object.Array{}
+	array.Elements = []object.Representation{array}
+
+	env := object.NewEnv()
+	env.Set("cyclic", array)
+
+	l := lexer.New(`json_stringify(cyclic);`)
+	p := parser.New(l)
+
+	evaluated := eval.Eval(p.ParseProgram(), env)
+	testEvaluatedObject(t, "json_stringify(cyclic)", evaluated, &object.Error{Message: "json_stringify: cyclic structure"})
+}
+
+func TestCollectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`map([1, 2, 3], fn(x) { x * 2 })[2];`, 6},
+		{`map([], fn(x) { x });`, "[]"},
+		{`map(["a", "b"], upper)[1];`, "B"},
+		{`filter(range(10), fn(x) { x / 2 * 2 == x });`, "[0, 2, 4, 6, 8]"},
+		{`reduce([1, 2, 3, 4], fn(acc, x) { acc + x });`, 10},
+		{`reduce([], fn(acc, x) { acc + x }, 0);`, 0},
+		{`reduce(["a", "b"], fn(acc, x) { acc + x }, ">");`, ">ab"},
+		{`each([1, 2], fn(x) { x });`, nil},
+		{`any([1, 2, 3], fn(x) { x > 2 });`, true},
+		{`any([], fn(x) { true });`, false},
+		{`all([1, 2, 3], fn(x) { x > 0 });`, true},
+		{`all([1, "a"], fn(x) { x > 1 });`, false},
+		{`sort_by([3, 1, 2], fn(a, b) { a - b });`, "[1, 2, 3]"},
+		{`sort_by([1.5, 3, 0.5], fn(a, b) { b - a });`, "[3, 1.5, 0.5]"},
+		{`let a = [2, 1]; sort_by(a, fn(a, b) { a - b }); a;`, "[2, 1]"},
+		{`zip([1, 2, 3], ["a", "b"]);`, "[[1, a], [2, b]]"},
+		{`range(3);`, "[0, 1, 2]"},
+		{`range(2, 5);`, "[2, 3, 4]"},
+		{`range(0, 10, 4);`, "[0, 4, 8]"},
+		{`range(5, 0, -2);`, "[5, 3, 1]"},
+		{`range(5, 0);`, "[]"},
+		{`range(-9223372036854775807 - 1, 9223372036854775807, 9223372036854775807);`,
+			"[-9223372036854775808, -1, 9223372036854775806]"},
+		{`range(0, -5, -9223372036854775807 - 1);`, "[0]"},
+
+		{`map([1], fn(x, y) { x });`, &object.Error{Message: "expected 2 arguments. got=1"}},
+		{`map([1, 2], fn(x) { x + true });`, &object.Error{Message: "type mismatch: INTEGER + BOOLEAN"}},
+		{`filter([1], fn(x) { x });`, &object.Error{Message: "filter: callback must return BOOLEAN, got=INTEGER"}},
+		{`reduce([], fn(acc, x) { acc });`, &object.Error{Message: "reduce: empty array without initial value"}},
+		{`sort_by([1, 2], fn(a, b) { true });`, &object.Error{Message: "sort_by: comparator must return NUMBER, got=BOOLEAN"}},
+		{`zip([1], 2);`, &object.Error{Message: "argument 2 to zip must be ARRAY, got=INTEGER"}},
+		{`range(0, 1, 0);`, &object.Error{Message: "range: step must not be zero"}},
+		{`range(0, 9223372036854775807, 2);`, &object.Error{Message: "range: result too large"}},
+		{`map(1, fn(x) { x });`, &object.Error{Message: "argument 1 to map must be ARRAY, got=INTEGER"}},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(string); ok && evaluated.Type() == object.ARRAY_OBJ {
+			if evaluated.Inspect() != expected {
+				t.Errorf("%s\n\texpected=%s. got=%s", tt.input, expected, evaluated.Inspect())
+			}
+			continue
+		}
+
+		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestCallbackErrorKeepsStack(t *testing.T) {
+	const input = `let deep = fn(n) { 1 + deep(n + 1) }; map([1], fn(x) { 0 + deep(x) });`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	evaluated := eval.New(eval.WithMaxCallDepth(5)).Eval(p.ParseProgram(), object.NewEnv())
+	err, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected *object.Error. got=%s", evaluated.Inspect())
+	}
+
+	expected := []string{"deep", "deep", "deep", "deep", "fn", "map"}
+	if strings.Join(err.Stack, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected stack %v. got=%v", expected, err.Stack)
+	}
+}
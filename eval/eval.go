@@ -1,7 +1,14 @@
 package eval
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
 
 	"github.com/EclesioMeloJunior/alang/ast"
 	"github.com/EclesioMeloJunior/alang/object"
@@ -14,11 +21,193 @@ var (
 	False *object.Boolean = &object.Boolean{Value: false}
 )
 
+// DefaultMaxCallDepth is the call depth an Evaluator allows
+// when no WithMaxCallDepth option is given
+const DefaultMaxCallDepth = 1000
+
+// maxStackTrace is how many of the innermost frames
+// are kept in the stack of a recursion depth error
+const maxStackTrace = 10
+
+type Option func(*Evaluator)
+
+// WithMaxCallDepth limits how many function calls can be nested
+// before the evaluation fails with a recursion depth error, a value
+// less or equal to zero disables the limit and lets a runaway recursion
+// grow the Go stack until the process crashes
+func WithMaxCallDepth(depth int) Option {
+	return func(e *Evaluator) {
+		e.maxCallDepth = depth
+	}
+}
+
+// WithoutOptimizations evaluates the programs as they are written, without
+// folding the constants nor removing the dead branches, eg. to debug the
+// evaluation. It applies to the programs prepared by the Evaluator, see Prepare
+func WithoutOptimizations() Option {
+	return func(e *Evaluator) {
+		e.noOptimizations = true
+	}
+}
+
+// Evaluator walks the AST holding the state of the evaluation,
+// like the stack of the functions being called. An Evaluator
+// must not be used by more than one goroutine at a time
+type Evaluator struct {
+	maxCallDepth    int
+	stepLimit       int64
+	noOptimizations bool
+	memoryLimit     int64
+	usage           Usage
+
+	// callStack holds the name of each function being called,
+	// the innermost call is the last element
+	callStack []string
+
+	// ctx is the context of the evaluation in progress
+	ctx context.Context
+
+	// modules caches the imported modules by their absolute path
+	// and importing holds the modules being evaluated, see importModule
+	modulePaths []string
+	modules     map[string]*object.Module
+	importing   []importFrame
+
+	// builtins are the builtins bound to this Evaluator
+	builtins map[string]*object.Builtin
+	rand     *rand.Rand
+
+	// stdout, stdin and fileAccess are used by the I/O builtins,
+	// see WithStdout, WithStdin and WithFileAccess
+	stdout     io.Writer
+	stdin      *bufio.Reader
+	fileAccess []string
+}
+
+func New(opts ...Option) *Evaluator {
+	e := &Evaluator{
+		maxCallDepth: DefaultMaxCallDepth,
+		ctx:          context.Background(),
+		modules:      make(map[string]*object.Module),
+		builtins:     make(map[string]*object.Builtin),
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		stdout:       os.Stdout,
+		stdin:        bufio.NewReader(os.Stdin),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	for name, fn := range evaluatorBuiltins {
+		e.builtins[name] = &object.Builtin{Name: name, Fn: fn(e)}
+	}
+
+	return e
+}
+
+// Eval evaluates the node using a new Evaluator with the default options
 func Eval(node ast.Node, env *object.Env) object.Representation {
+	return New().Eval(node, env)
+}
+
+// EvalContext evaluates the node using a new Evaluator with the default
+// options, the evaluation stops when the context is done
+func EvalContext(ctx context.Context, node ast.Node, env *object.Env) object.Representation {
+	return New().EvalContext(ctx, node, env)
+}
+
+func (e *Evaluator) Eval(node ast.Node, env *object.Env) object.Representation {
+	return e.EvalContext(context.Background(), node, env)
+}
+
+// EvalContext evaluates the node checking the context at every function call,
+// including the ones in tail position that work as the language loops. Once the
+// context is done the evaluation stops with an error whose Err field is the
+// context error, see IsCancelled
+func (e *Evaluator) EvalContext(ctx context.Context, node ast.Node, env *object.Env) object.Representation {
+	previous := e.ctx
+	e.ctx = ctx
+	defer func() {
+		e.ctx = previous
+	}()
+
+	markTailCalls(node)
+	return e.eval(node, env)
+}
+
+// Apply calls the function with the arguments as if it was called from the
+// source by the given name, the evaluation stops when the context is done
+func (e *Evaluator) Apply(ctx context.Context, name string, fn object.Representation, args []object.Representation) object.Representation {
+	previous := e.ctx
+	e.ctx = ctx
+	defer func() {
+		e.ctx = previous
+	}()
+
+	return e.apply(name, fn, args)
+}
+
+// ApplyFunction calls the function with the arguments from inside the evaluation
+// in progress, it is how builtins call back into the script. Errors, including
+// the ones returned by the callback, are returned as they are so the builtin
+// can stop and propagate them
+func (e *Evaluator) ApplyFunction(fn object.Representation, args ...object.Representation) object.Representation {
+	return e.apply("fn", fn, args)
+}
+
+// apply calls any callable representation: functions, builtins and host functions
+func (e *Evaluator) apply(name string, fn object.Representation, args []object.Representation) object.Representation {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if len(args) != len(fn.Parameters) {
+			return errorF(object.ArityError, "expected %d arguments. got=%d",
+				len(fn.Parameters), len(args))
+		}
+
+		return e.applyFunction(name, fn, args)
+
+	case *object.Builtin:
+		// the builtin is in the call stack while it runs
+		// so the functions it calls back show where they came from
+		e.callStack = append(e.callStack, name)
+		defer func() {
+			e.callStack = e.callStack[:len(e.callStack)-1]
+		}()
+
+		return e.allocateObject(fn.Fn(args...))
+
+	case *object.HostValue:
+		return e.allocateObject(callHost(fn, args))
+
+	default:
+		return errorF(object.TypeError, "not a function: %s", fn.Type())
+	}
+}
+
+// eval evaluates the node, the errors raised by it get its position unless
+// a node inside it, the one that actually failed, already gave them one
+func (e *Evaluator) eval(node ast.Node, env *object.Env) object.Representation {
+	rep := e.evalNode(node, env)
+	if err, ok := rep.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+	}
+
+	return rep
+}
+
+func (e *Evaluator) evalNode(node ast.Node, env *object.Env) object.Representation {
+	if err := e.step(); err != nil {
+		return err
+	}
+
 	switch node := node.(type) {
 
 	case *ast.IntegerLiteral:
-		return &object.Integer{Value: node.Value}
+		return e.allocateObject(&object.Integer{Value: node.Value})
+
+	case *ast.FloatLiteral:
+		return e.allocateObject(&object.Float{Value: node.Value})
 
 	case *ast.BooleanLiteral:
 		// avoid to create new instances
@@ -32,51 +221,79 @@ func Eval(node ast.Node, env *object.Env) object.Representation {
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{
+		return e.allocateObject(&object.Function{
 			Parameters: params,
 			Body:       body,
 			Env:        env,
-		}
+			Scope:      node.Scope,
+		})
 
 	case *ast.CallExpression:
-		representation := Eval(node.Function, env)
+		representation := e.eval(node.Function, env)
 		if isError(representation) {
 			return representation
 		}
 
-		function, ok := representation.(*object.Function)
-		if !ok {
-			return errorF("not a function: %s", representation.Type())
+		switch callee := representation.(type) {
+		case *object.Function:
+			if len(node.Arguments) != len(callee.Parameters) {
+				return errorF(object.ArityError, "expected %d arguments. got=%d",
+					len(callee.Parameters), len(node.Arguments))
+			}
+		case *object.Builtin, *object.HostValue:
+		default:
+			return errorF(object.TypeError, "not a function: %s", representation.Type())
 		}
 
-		if len(node.Arguments) != len(function.Parameters) {
-			return errorF("expected %d arguments. got=%d",
-				len(function.Parameters), len(node.Arguments))
+		arguments, err := e.evalExpressions(node.Arguments, env)
+		if err != nil {
+			return err
 		}
 
-		enclosedEnv := object.NewEnclosedEnv(function.Env)
-		if len(node.Arguments) != 0 {
-			arguments := make([]object.Representation, len(node.Arguments))
+		name := node.Function.String()
+		if function, ok := representation.(*object.Function); ok && node.Tail {
+			return &tailCall{name: name, function: function, arguments: arguments}
+		}
 
-			for idx, argument := range node.Arguments {
-				evaluatedArg := Eval(argument, env)
-				if isError(evaluatedArg) {
-					return evaluatedArg
-				}
+		return e.apply(name, representation, arguments)
 
-				arguments[idx] = evaluatedArg
-			}
+	case *ast.StringLiteral:
+		return e.allocateObject(&object.String{Value: node.Value})
 
-			for idx, param := range function.Parameters {
-				enclosedEnv.Set(param.Value, arguments[idx])
-			}
+	case *ast.ArrayLiteral:
+		elements, err := e.evalExpressions(node.Elements, env)
+		if err != nil {
+			return err
 		}
 
-		evaluatedFnBody := Eval(function.Body, enclosedEnv)
-		return unwrapReturnValue(evaluatedFnBody)
+		return e.allocateObject(&object.Array{Elements: elements})
+
+	case *ast.HashLiteral:
+		return e.evalHashLiteral(node, env)
+
+	case *ast.IndexExpression:
+		left := e.eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		index := e.eval(node.Index, env)
+		if isError(index) {
+			return index
+		}
+
+		return evalIndexExpression(left, index)
+
+	case *ast.SelectorExpression:
+		left := e.eval(node.Left, env)
+		if isError(left) {
+			return left
+		}
+
+		return evalSelectorExpression(left, node.Selector.Value)
 
 	case *ast.PrefixExpression:
-		right := Eval(node.Right, env)
+		right := e.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
@@ -85,66 +302,342 @@ func Eval(node ast.Node, env *object.Env) object.Representation {
 		case token.BANG:
 			return evalBangPrefixOperatorExpression(right)
 		case token.MINUS:
-			return evalMinusPrefixOperatorExpression(right)
+			return e.allocateObject(evalMinusPrefixOperatorExpression(right))
 		default:
-			return errorF("unknow operator: %s%s", node.Operator, right.Type())
+			return errorF(object.TypeError, "unknow operator: %s%s", node.Operator, right.Type())
 		}
 
 	case *ast.InfixExpression:
-		left := Eval(node.Left, env)
+		left := e.eval(node.Left, env)
 		if isError(left) {
 			return left
 		}
 
-		right := Eval(node.Right, env)
+		right := e.eval(node.Right, env)
 		if isError(right) {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		return e.allocateObject(evalInfixExpression(node.Operator, left, right))
 
 	case *ast.ReturnStatement:
-		returned := Eval(node.Value, env)
+		returned := e.eval(node.Value, env)
+		if isError(returned) {
+			return returned
+		}
+
 		return &object.Return{
 			Value: returned,
 		}
 
 	case *ast.LetStatement:
-		valueToBind := Eval(node.Value, env)
+		valueToBind := e.evalLetValue(node, env)
 		if isError(valueToBind) {
 			return valueToBind
 		}
 
-		env.Set(node.Name.Value, valueToBind)
+		if err := e.allocate(bindingSize); err != nil {
+			return err
+		}
+
+		bind(env, node.Name, valueToBind)
 		return nil
 
+	case *ast.ImportStatement:
+		return e.evalImportStatement(node, env)
+
+	case *ast.ThrowStatement:
+		return e.evalThrowStatement(node, env)
+
+	case *ast.TryExpression:
+		return e.evalTryExpression(node, env)
+
 	case *ast.Identifier:
-		stored, has := env.Get(node.Value)
-		if !has {
-			return errorF("identifier not found: %s", node.Value)
+		if node.Resolved {
+			// a slot that is not set yet, eg. by a let in a branch not
+			// taken, falls back to the name as an unresolved identifier
+			if stored, has := env.GetSlot(node.Depth, node.Slot); has {
+				return stored
+			}
+		}
+
+		if stored, has := env.Get(node.Value); has {
+			return stored
+		}
+
+		if builtin, has := e.lookupBuiltin(node.Value); has {
+			return builtin
 		}
 
-		return stored
+		return errorF(object.NameError, "identifier not found: %s", node.Value)
 
 	case *ast.BlockStatement:
-		return evalBlockStatements(node.Statements, env)
+		return e.evalBlockStatements(node.Statements, env)
 
 	case *ast.IfExpression:
-		return evalIfExpression(node, env)
+		return e.evalIfExpression(node, env)
 
 	case *ast.Program:
-		return evalProgram(node.Statements, env)
+		return e.evalProgram(node.Statements, env)
 
 	case *ast.ExpressionStatement:
-		return Eval(node.Expression, env)
+		return e.eval(node.Expression, env)
+
+	default:
+		return nil
+	}
+}
+
+// evalLetValue evaluates the value of a let statement. When the value
+// is a function literal the function is created with letrec semantics:
+// it closes over an environment where its own name is already bound to
+// itself, so recursive calls keep working even if the outer binding is
+// later shadowed or rebound, eg. `let f = fn(n) { f(n - 1) }; let g = f; let f = 0;`
+// Mutually recursive functions declared by consecutive lets in the same
+// scope resolve each other through the shared enclosing environment at call time
+func (e *Evaluator) evalLetValue(node *ast.LetStatement, env *object.Env) object.Representation {
+	literal, ok := node.Value.(*ast.FunctionLiteral)
+	if !ok {
+		return e.eval(node.Value, env)
+	}
+
+	if err := e.allocate(envSize + bindingSize); err != nil {
+		return err
+	}
+
+	fnEnv := object.NewEnclosedEnv(env)
+	if literal.Scope != nil {
+		// the resolver gives the scope of the name a single slot
+		fnEnv = object.NewFrame(env, []string{node.Name.Value})
+	}
+
+	function := &object.Function{
+		Parameters: literal.Parameters,
+		Body:       literal.Body,
+		Env:        fnEnv,
+		Scope:      literal.Scope,
+	}
+
+	fnEnv.Set(node.Name.Value, function)
+	return e.allocateObject(function)
+}
+
+// newEnv creates the environment of a scope, a frame when the
+// resolver annotated the scope and a map based one otherwise
+func newEnv(outer *object.Env, scope *ast.Scope) *object.Env {
+	if scope == nil {
+		return object.NewEnclosedEnv(outer)
+	}
+
+	return object.NewFrame(outer, scope.Names)
+}
+
+// bind sets the value of the name declared in the environment
+func bind(env *object.Env, name *ast.Identifier, value object.Representation) {
+	if name.Resolved && name.Depth == 0 && env.SetSlot(name.Slot, value) {
+		return
+	}
+
+	env.Set(name.Value, value)
+}
+
+// evalExpressions evaluates the expressions in order, stopping at the first error
+func (e *Evaluator) evalExpressions(exps []ast.Expression, env *object.Env) ([]object.Representation, *object.Error) {
+	evaluated := make([]object.Representation, len(exps))
+
+	for idx, exp := range exps {
+		rep := e.eval(exp, env)
+		if err, ok := rep.(*object.Error); ok {
+			return nil, err
+		}
+
+		evaluated[idx] = rep
+	}
+
+	return evaluated, nil
+}
+
+func (e *Evaluator) evalHashLiteral(node *ast.HashLiteral, env *object.Env) object.Representation {
+	hash := object.NewHash()
+
+	for _, pair := range node.Pairs {
+		key := e.eval(pair.Key, env)
+		if isError(key) {
+			return key
+		}
+
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return errorF(object.TypeError, "unusable as hash key: %s", key.Type())
+		}
+
+		value := e.eval(pair.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashable, value)
+	}
+
+	return e.allocateObject(hash)
+}
+
+func evalIndexExpression(left, index object.Representation) object.Representation {
+	switch left := left.(type) {
+	case *object.Array:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return errorF(object.TypeError, "array index must be an INTEGER, got=%s", index.Type())
+		}
+
+		if idx.Value < 0 || idx.Value >= int64(len(left.Elements)) {
+			return errorF(object.IndexError, "index out of range: %d (len %d)", idx.Value, len(left.Elements))
+		}
+
+		return left.Elements[idx.Value]
+
+	case *object.String:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return errorF(object.TypeError, "string index must be an INTEGER, got=%s", index.Type())
+		}
+
+		// indexed by characters, as substr and chars do
+		runes := []rune(left.Value)
+		if idx.Value < 0 || idx.Value >= int64(len(runes)) {
+			return errorF(object.IndexError, "index out of range: %d (len %d)", idx.Value, len(runes))
+		}
+
+		return &object.String{Value: string(runes[idx.Value])}
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return errorF(object.TypeError, "unusable as hash key: %s", index.Type())
+		}
+
+		value, has := left.Get(key)
+		if !has {
+			return Null
+		}
+
+		return value
 
+	case *object.HostValue:
+		return indexHost(left, index)
+
+	default:
+		return errorF(object.TypeError, "index operator not supported: %s", left.Type())
+	}
+}
+
+func evalSelectorExpression(left object.Representation, name string) object.Representation {
+	switch left := left.(type) {
+	case *object.HostValue:
+		return selectHost(left, name)
+	case *object.Module:
+		return selectModule(left, name)
+	case *object.ErrorValue:
+		return selectError(left, name)
+	default:
+		return errorF(object.NameError, "type %s has no field or method %s", left.Type(), name)
+	}
+}
+
+// applyFunction calls the function with the already evaluated arguments,
+// the name is the callee as written in the source and is recorded in the
+// call stack so a recursion depth error can show where it happened. Calls
+// in tail position, see markTailCalls, are executed in a loop reusing the
+// current stack frame, so they count once against the maximum call depth
+func (e *Evaluator) applyFunction(name string, function *object.Function, arguments []object.Representation) object.Representation {
+	if e.maxCallDepth > 0 && len(e.callStack) >= e.maxCallDepth {
+		err := errorF(object.LimitError, "maximum recursion depth exceeded (%d)", e.maxCallDepth)
+		err.Stack = e.stackTrace(name)
+		return err
+	}
+
+	e.callStack = append(e.callStack, name)
+	defer func() {
+		e.callStack = e.callStack[:len(e.callStack)-1]
+	}()
+
+	for {
+		if err := e.checkContext(); err != nil {
+			return err
+		}
+
+		if err := e.allocate(envSize + int64(len(arguments))*bindingSize); err != nil {
+			return err
+		}
+
+		enclosedEnv := newEnv(function.Env, function.Scope)
+		for idx, param := range function.Parameters {
+			bind(enclosedEnv, param, arguments[idx])
+		}
+
+		evaluatedFnBody := unwrapReturnValue(e.eval(function.Body, enclosedEnv))
+		if err, ok := evaluatedFnBody.(*object.Error); ok && err.Stack == nil {
+			// the innermost function the error escapes from records the stack
+			err.Stack = e.stackTrace()
+		}
+
+		call, ok := evaluatedFnBody.(*tailCall)
+		if !ok {
+			return evaluatedFnBody
+		}
+
+		function, arguments = call.function, call.arguments
+		e.callStack[len(e.callStack)-1] = call.name
+	}
+}
+
+// checkContext returns the cancellation error if the context is done
+func (e *Evaluator) checkContext() *object.Error {
+	select {
+	case <-e.ctx.Done():
+		err := errorF(object.Cancelled, "evaluation cancelled: %s", e.ctx.Err())
+		err.Err = e.ctx.Err()
+		err.Stack = e.stackTrace()
+		return err
 	default:
 		return nil
 	}
 }
 
-func evalIfExpression(node *ast.IfExpression, env *object.Env) object.Representation {
-	condition := Eval(node.Condition, env)
+// IsCancelled reports whether the representation is the error an
+// evaluation returns when it is stopped by its context
+func IsCancelled(r object.Representation) bool {
+	err, ok := r.(*object.Error)
+	if !ok || err.Err == nil {
+		return false
+	}
+
+	return errors.Is(err.Err, context.Canceled) ||
+		errors.Is(err.Err, context.DeadlineExceeded)
+}
+
+// stackTrace returns the innermost frames of the call stack, starting by
+// the calls that are about to happen, if the stack is deeper than maxStackTrace
+// the remaining frames are summarized in a last line
+func (e *Evaluator) stackTrace(calling ...string) []string {
+	frames := make([]string, 0, len(e.callStack)+len(calling))
+	frames = append(frames, e.callStack...)
+	frames = append(frames, calling...)
+
+	trace := make([]string, 0, maxStackTrace+1)
+	for idx := len(frames) - 1; idx >= 0 && len(trace) < maxStackTrace; idx-- {
+		trace = append(trace, frames[idx])
+	}
+
+	if omitted := len(frames) - len(trace); omitted > 0 {
+		trace = append(trace, fmt.Sprintf("... %d more", omitted))
+	}
+
+	return trace
+}
+
+func (e *Evaluator) evalIfExpression(node *ast.IfExpression, env *object.Env) object.Representation {
+	condition := e.eval(node.Condition, env)
 
 	if isError(condition) {
 		// cannot evaluate since the Expression is not valid
@@ -152,34 +645,34 @@ func evalIfExpression(node *ast.IfExpression, env *object.Env) object.Representa
 	}
 
 	if condition.Type() != object.BOOLEAN_OBJ {
-		return errorF("condition must evaluate to a boolean, got=%s", condition.Type())
+		return errorF(object.TypeError, "condition must evaluate to a boolean, got=%s", condition.Type())
 	}
 
 	switch condition {
 	case Null:
 		if node.Alternative != nil {
-			return Eval(node.Alternative, env)
+			return e.eval(node.Alternative, env)
 		}
 		return Null
 
 	case False:
 		if node.Alternative != nil {
-			return Eval(node.Alternative, env)
+			return e.eval(node.Alternative, env)
 		}
 		return Null
 
 	case True:
-		return Eval(node.Consequence, env)
+		return e.eval(node.Consequence, env)
 	default:
-		return Eval(node.Consequence, env)
+		return e.eval(node.Consequence, env)
 	}
 }
 
-func evalBlockStatements(stmts []ast.Statement, env *object.Env) object.Representation {
+func (e *Evaluator) evalBlockStatements(stmts []ast.Statement, env *object.Env) object.Representation {
 	var rep object.Representation
 
 	for _, stmt := range stmts {
-		rep = Eval(stmt, env)
+		rep = e.eval(stmt, env)
 
 		switch rep := rep.(type) {
 		case *object.Return:
@@ -192,11 +685,11 @@ func evalBlockStatements(stmts []ast.Statement, env *object.Env) object.Represen
 	return rep
 }
 
-func evalProgram(stmts []ast.Statement, env *object.Env) object.Representation {
+func (e *Evaluator) evalProgram(stmts []ast.Statement, env *object.Env) object.Representation {
 	var rep object.Representation
 
 	for _, stmt := range stmts {
-		rep = Eval(stmt, env)
+		rep = e.eval(stmt, env)
 
 		switch rep := rep.(type) {
 		case *object.Return:
@@ -228,12 +721,21 @@ func evalMinusPrefixOperatorExpression(right object.Representation) object.Repre
 		return &object.Integer{
 			Value: -right.Value,
 		}
+	case *object.Float:
+		return &object.Float{
+			Value: -right.Value,
+		}
 	default:
-		return errorF("unknown operator: -%s", right.Type())
+		return errorF(object.TypeError, "unknown operator: -%s", right.Type())
 	}
 }
 
 func evalInfixExpression(op string, left, right object.Representation) object.Representation {
+	// null, eg. a missing hash key, is only equal to null
+	if (op == "==" || op == "!=") && (left.Type() == object.NULL_OBJ || right.Type() == object.NULL_OBJ) {
+		equal := left.Type() == right.Type()
+		return nativeBoolToBoolean(equal == (op == "=="))
+	}
 
 	switch l := left.(type) {
 	case *object.Integer:
@@ -241,8 +743,21 @@ func evalInfixExpression(op string, left, right object.Representation) object.Re
 		switch r := right.(type) {
 		case *object.Integer:
 			return evalIntegerInfixExpression(op, l, r)
+		case *object.Float:
+			return evalFloatInfixExpression(op, float64(l.Value), r.Value)
 		default:
-			return errorF("type mismatch: %s %s %s", left.Type(), op, right.Type())
+			return errorF(object.TypeError, "type mismatch: %s %s %s", left.Type(), op, right.Type())
+		}
+
+	case *object.Float:
+
+		switch r := right.(type) {
+		case *object.Integer:
+			return evalFloatInfixExpression(op, l.Value, float64(r.Value))
+		case *object.Float:
+			return evalFloatInfixExpression(op, l.Value, r.Value)
+		default:
+			return errorF(object.TypeError, "type mismatch: %s %s %s", left.Type(), op, right.Type())
 		}
 
 	case *object.Boolean:
@@ -251,11 +766,20 @@ func evalInfixExpression(op string, left, right object.Representation) object.Re
 		case *object.Boolean:
 			return evalBooleanInfixExpression(op, l, r)
 		default:
-			return errorF("type mismatch: %s %s %s", left.Type(), op, right.Type())
+			return errorF(object.TypeError, "type mismatch: %s %s %s", left.Type(), op, right.Type())
+		}
+
+	case *object.String:
+
+		switch r := right.(type) {
+		case *object.String:
+			return evalStringInfixExpression(op, l, r)
+		default:
+			return errorF(object.TypeError, "type mismatch: %s %s %s", left.Type(), op, right.Type())
 		}
 
 	default:
-		return errorF("unknown operator: %s %s %s", left.Type(), op, right.Type())
+		return errorF(object.TypeError, "unknown operator: %s %s %s", left.Type(), op, right.Type())
 	}
 
 }
@@ -275,6 +799,10 @@ func evalIntegerInfixExpression(op string, left, right *object.Integer) object.R
 			Value: left.Value * right.Value,
 		}
 	case token.SLASH:
+		if right.Value == 0 {
+			return errorF(object.ZeroDivision, "division by zero: %d / 0", left.Value)
+		}
+
 		return &object.Integer{
 			Value: int64(left.Value / right.Value),
 		}
@@ -299,7 +827,36 @@ func evalIntegerInfixExpression(op string, left, right *object.Integer) object.R
 		}
 		return False
 	default:
-		return errorF("unknown operator: %s %s %s", left.Type(), op, right.Type())
+		return errorF(object.TypeError, "unknown operator: %s %s %s", left.Type(), op, right.Type())
+	}
+}
+
+// evalFloatInfixExpression evaluates operations between floats,
+// an integer operand is converted to a float before it gets here
+func evalFloatInfixExpression(op string, left, right float64) object.Representation {
+	switch op {
+	case token.PLUS:
+		return &object.Float{Value: left + right}
+	case token.MINUS:
+		return &object.Float{Value: left - right}
+	case token.ASTHERISC:
+		return &object.Float{Value: left * right}
+	case token.SLASH:
+		if right == 0 {
+			return errorF(object.ZeroDivision, "division by zero: %s / 0", (&object.Float{Value: left}).Inspect())
+		}
+
+		return &object.Float{Value: left / right}
+	case token.GT:
+		return nativeBoolToBoolean(left > right)
+	case token.LT:
+		return nativeBoolToBoolean(left < right)
+	case token.NOT_EQ:
+		return nativeBoolToBoolean(left != right)
+	case token.EQ:
+		return nativeBoolToBoolean(left == right)
+	default:
+		return errorF(object.TypeError, "unknown operator: %s %s %s", object.FLOAT_OBJ, op, object.FLOAT_OBJ)
 	}
 }
 
@@ -316,7 +873,28 @@ func evalBooleanInfixExpression(op string, left, right *object.Boolean) object.R
 		}
 		return False
 	default:
-		return errorF("unknown operator: %s %s %s", left.Type(), op, right.Type())
+		return errorF(object.TypeError, "unknown operator: %s %s %s", left.Type(), op, right.Type())
+	}
+}
+
+func evalStringInfixExpression(op string, left, right *object.String) object.Representation {
+	switch op {
+	case token.PLUS:
+		return &object.String{
+			Value: left.Value + right.Value,
+		}
+	case token.NOT_EQ:
+		if left.Value != right.Value {
+			return True
+		}
+		return False
+	case token.EQ:
+		if left.Value == right.Value {
+			return True
+		}
+		return False
+	default:
+		return errorF(object.TypeError, "unknown operator: %s %s %s", left.Type(), op, right.Type())
 	}
 }
 
@@ -338,8 +916,9 @@ func isError(r object.Representation) bool {
 	}
 }
 
-func errorF(format string, o ...interface{}) *object.Error {
+func errorF(kind object.ErrorKind, format string, o ...interface{}) *object.Error {
 	return &object.Error{
+		Kind:    kind,
 		Message: fmt.Sprintf(format, o...),
 	}
 }
@@ -1,12 +1,17 @@
 package eval_test
 
 import (
+	"context"
+	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/EclesioMeloJunior/alang/eval"
 	"github.com/EclesioMeloJunior/alang/lexer"
 	"github.com/EclesioMeloJunior/alang/object"
 	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/resolver"
 )
 
 func TestEvaluationLiteralObjects(t *testing.T) {
@@ -60,8 +65,7 @@ func TestEvaluationLiteralObjects(t *testing.T) {
 	}
 
 	for _, tt := range testcases {
-		evaluated := testEval(tt.input)
-		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+		testEvalPaths(t, tt.input, tt.expected)
 	}
 }
 
@@ -80,8 +84,7 @@ func TestEvaluatesConditions(t *testing.T) {
 	}
 
 	for _, tt := range testcases {
-		evaluated := testEval(tt.input)
-		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+		testEvalPaths(t, tt.input, tt.expected)
 	}
 }
 
@@ -103,8 +106,7 @@ func TestEvaluatesReturns(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evalulated := testEval(tt.input)
-		testEvaluatedObject(t, tt.input, evalulated, tt.expected)
+		testEvalPaths(t, tt.input, tt.expected)
 	}
 }
 
@@ -132,8 +134,7 @@ func TestErrorWhileEvaluating(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evaluated := testEval(tt.input)
-		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+		testEvalPaths(t, tt.input, tt.expected)
 	}
 }
 
@@ -149,8 +150,7 @@ func TestEvalutaionLetStatements(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		evaluated := testEval(tt.input)
-		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+		testEvalPaths(t, tt.input, tt.expected)
 	}
 }
 
@@ -167,7 +167,7 @@ func TestEvaluatesFunction(t *testing.T) {
 		t.Fatalf("expected 2 parameters. got=%d", len(function.Parameters))
 	}
 
-	const expectedBody = "(x + 2)"
+	const expectedBody = "{ (x + 2); }"
 	if function.Body.String() != expectedBody {
 		t.Fatalf("expected %s body. got=%s", expectedBody, function.Body.String())
 	}
@@ -189,12 +189,303 @@ func TestEvaluatesFunctionsCalls(t *testing.T) {
 		}},
 	}
 
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestEvaluatesRecursiveFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let fact = fn(n) { if (n == 0) { return 1; } return n * fact(n - 1); }; fact(5);`, 120},
+		{`let outer = fn() {
+			let count = fn(n) { if (n == 0) { return 0; } return 1 + count(n - 1); };
+			count(10);
+		}; outer();`, 10},
+		{`let outer = fn(x) {
+			let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } };
+			let isOdd = fn(n) { if (n == 0) { false } else { isEven(n - 1) } };
+			isEven(x);
+		}; outer(10);`, true},
+		{`let outer = fn(x) {
+			let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } };
+			let isOdd = fn(n) { if (n == 0) { false } else { isEven(n - 1) } };
+			isOdd(x);
+		}; outer(7);`, true},
+		{`let f = fn(n) { if (n == 0) { return 0; } return f(n - 1); };
+		let g = f;
+		let f = 5;
+		g(3);`, 0},
+		{`let makeCounter = fn(start) {
+			let loop = fn(n) { if (n == start) { return n; } return loop(n + 1); };
+			loop;
+		};
+		let a = makeCounter(3);
+		let b = makeCounter(5);
+		a(0) + b(0);`, 8},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestRecursionDepthLimit(t *testing.T) {
+	const input = `let f = fn(n) { 1 + f(n + 1) }; f(0);`
+
+	evaluated := testEval(input)
+	testEvaluatedObject(t, input, evaluated, &object.Error{
+		Message: "maximum recursion depth exceeded (1000)",
+	})
+
+	err := evaluated.(*object.Error)
+	if len(err.Stack) != 11 {
+		t.Fatalf("expected 11 stack lines. got=%d (%v)", len(err.Stack), err.Stack)
+	}
+
+	if err.Stack[0] != "f" {
+		t.Fatalf("expected innermost frame f. got=%s", err.Stack[0])
+	}
+
+	const expectedSummary = "... 991 more"
+	if err.Stack[10] != expectedSummary {
+		t.Fatalf("expected %q. got=%q", expectedSummary, err.Stack[10])
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let f = fn(n) { if (n == 0) { return 0; } return 1 + f(n - 1); }; f(5);`, 5},
+		{`let f = fn(n) { if (n == 0) { return 0; } return 1 + f(n - 1); }; f(6);`, &object.Error{
+			Message: "maximum recursion depth exceeded (6)",
+		}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		evaluator := eval.New(eval.WithMaxCallDepth(6))
+		evaluated := evaluator.Eval(p.ParseProgram(), object.NewEnv())
+		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestTailCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let countdown = fn(n) { if (n == 0) { return 0; } return countdown(n - 1); };
+		countdown(1000000);`, 0},
+		{`let countdown = fn(n) { if (n == 0) { 0 } else { countdown(n - 1) } };
+		countdown(100000);`, 0},
+		{`let fact = fn(n, acc) { if (n == 0) { return acc; } return fact(n - 1, acc * n); };
+		fact(20, 1);`, 2432902008176640000},
+		{`let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } };
+		let isOdd = fn(n) { if (n == 0) { false } else { isEven(n - 1) } };
+		isEven(100001);`, false},
+		{`let sum = fn(n, acc) {
+			if (n == 0) {
+				return acc;
+			}
+			sum(n - 1, acc + n);
+		};
+		sum(100000, 0);`, 5000050000},
+		{`let f = fn(n) { if (n == 0) { return 0; } f(n - 1); 1 + f(n - 1) }; f(3);`, 3},
+		{`let f = fn(n) { if (n == 0) { return 0; } return f(true + n); }; f(3);`, &object.Error{
+			Message: "type mismatch: BOOLEAN + INTEGER",
+		}},
+	}
+
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
 		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
 	}
 }
 
+func TestEvalContextCancellation(t *testing.T) {
+	const input = `let loop = fn(n) { loop(n + 1) }; loop(0);`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	evaluated := eval.EvalContext(ctx, p.ParseProgram(), object.NewEnv())
+	testEvaluatedObject(t, input, evaluated, &object.Error{
+		Message: "evaluation cancelled: context deadline exceeded",
+	})
+
+	if !eval.IsCancelled(evaluated) {
+		t.Fatalf("expected a cancellation error. got=%s", evaluated.Inspect())
+	}
+
+	err := evaluated.(*object.Error)
+	if !errors.Is(err.Err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded. got=%v", err.Err)
+	}
+
+	if len(err.Stack) != 1 || err.Stack[0] != "loop" {
+		t.Fatalf("expected stack [loop]. got=%v", err.Stack)
+	}
+
+	if eval.IsCancelled(testEval("x;")) {
+		t.Fatalf("identifier not found must not be a cancellation error")
+	}
+}
+
+func TestEvaluationLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []eval.Option
+		expected *object.Error
+		err      error
+	}{
+		{
+			input:    `let loop = fn(n) { loop(n + 1) }; loop(0);`,
+			opts:     []eval.Option{eval.WithStepLimit(10000)},
+			expected: &object.Error{Message: "step limit exceeded (10000)"},
+			err:      eval.ErrStepLimitExceeded,
+		},
+		{
+			input:    `let loop = fn(n) { loop(n + 1) }; loop(0);`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+		{
+			input:    `range(100000000);`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+		{
+			// fails before creating the string
+			input:    `repeat("ab", 1000000000);`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+		{
+			input:    `replace(repeat("a", 100000), "", repeat("b", 100000));`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+		{
+			input:    `let s = repeat("a", 300000); join([s, s, s, s], "");`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+		{
+			input:    `let s = repeat("a", 300000); format("{}{}{}{}", s, s, s, s);`,
+			opts:     []eval.Option{eval.WithMemoryLimit(1 << 20)},
+			expected: &object.Error{Message: "memory limit exceeded (1048576 bytes)"},
+			err:      eval.ErrMemoryLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		evaluator := eval.New(tt.opts...)
+		evaluated := evaluator.Eval(p.ParseProgram(), object.NewEnv())
+		testEvaluatedObject(t, tt.input, evaluated, tt.expected)
+
+		err := evaluated.(*object.Error)
+		if !errors.Is(err.Err, tt.err) {
+			t.Fatalf("%s\n\texpected %v. got=%v", tt.input, tt.err, err.Err)
+		}
+	}
+}
+
+func TestEvaluationUsage(t *testing.T) {
+	const input = `let x = 5; x + 1;`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	evaluator := eval.New(eval.WithStepLimit(7), eval.WithMemoryLimit(80))
+	evaluated := evaluator.Eval(p.ParseProgram(), object.NewEnv())
+	testEvaluatedObject(t, input, evaluated, 6)
+
+	// program, let, 5, expression statement, infix, x and 1
+	// memory is the integers 5, 1 and 6 plus the binding of x
+	expected := eval.Usage{Steps: 7, Memory: 80}
+	if usage := evaluator.Usage(); usage != expected {
+		t.Fatalf("expected usage %+v. got=%+v", expected, usage)
+	}
+}
+
+func TestEvaluatesFloats(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"3.5;", 3.5},
+		{"-2.25;", -2.25},
+		{"1.5 + 1.5;", 3.0},
+		{"1 + 0.5;", 1.5},
+		{"0.5 * 4;", 2.0},
+		{"7 / 2.0;", 3.5},
+		{"1.5 > 1;", true},
+		{"2 == 2.0;", true},
+		{"0.1 + 0.2 != 0.3;", true},
+		{"10 / 0;", &object.Error{Message: "division by zero: 10 / 0"}},
+		{"1.5 / 0;", &object.Error{Message: "division by zero: 1.5 / 0"}},
+		{"1.5 + true;", &object.Error{Message: "type mismatch: FLOAT + BOOLEAN"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestEvaluatesCollections(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"hello";`, "hello"},
+		{`"hello" + " " + "world";`, "hello world"},
+		{`"a" == "a";`, true},
+		{`"a" != "a";`, false},
+		{`"abc"[1];`, "b"},
+		{`"héllo"[1];`, "é"},
+		{`"héllo"[4];`, "o"},
+		{`"héllo"[5];`, &object.Error{Message: "index out of range: 5 (len 5)"}},
+		{`"a" - "b";`, &object.Error{Message: "unknown operator: STRING - STRING"}},
+		{`"a" + 1;`, &object.Error{Message: "type mismatch: STRING + INTEGER"}},
+		{`[1, 2 * 2, 3][1];`, 4},
+		{`let xs = [1, 2, 3]; xs[0] + xs[2];`, 4},
+		{`[1, 2][2];`, &object.Error{Message: "index out of range: 2 (len 2)"}},
+		{`[1, 2][true];`, &object.Error{Message: "array index must be an INTEGER, got=BOOLEAN"}},
+		{`let key = "two"; {"one": 1, key: 2}["two"];`, 2},
+		{`{1: true, false: 0}[1];`, true},
+		{`{"one": 1}["missing"];`, nil},
+		{`let h = {"a": 1}; h["b"] == h["c"];`, true},
+		{`let h = {"a": 1}; h["b"] != h["c"];`, false},
+		{`let h = {"a": 1}; h["a"] == h["b"];`, false},
+		{`let h = {"a": 1}; h["b"] != h["a"];`, true},
+		{`let h = {"a": 1}; h["b"] + 1;`, &object.Error{Message: "unknown operator: NULL + INTEGER"}},
+		{`{fn(x) { x }: 1};`, &object.Error{Message: "unusable as hash key: FUNCTION_OBJ"}},
+		{`{"one": 1}[[1]];`, &object.Error{Message: "unusable as hash key: ARRAY"}},
+		{`5[0];`, &object.Error{Message: "index operator not supported: INTEGER"}},
+		{`let user = {"name": "alang"}; user.name;`, &object.Error{Message: "type HASH has no field or method name"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
 func testEval(input string) object.Representation {
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -203,6 +494,23 @@ func testEval(input string) object.Representation {
 	return eval.Eval(prog, object.NewEnv())
 }
 
+// testEvalResolved evaluates the program resolved, with the
+// names of the functions and catch blocks bound in slots
+func testEvalResolved(input string) object.Representation {
+	prog := parser.New(lexer.New(input)).ParseProgram()
+	resolver.Resolve(prog)
+	return eval.Eval(prog, object.NewEnv())
+}
+
+// testEvalPaths checks the program evaluates to the expected value
+// with its names bound in maps, as written, and resolved in slots
+func testEvalPaths(t *testing.T, input string, expected interface{}) {
+	t.Helper()
+
+	testEvaluatedObject(t, input, testEval(input), expected)
+	testEvaluatedObject(t, input+" (resolved)", testEvalResolved(input), expected)
+}
+
 func testEvaluatedObject(t *testing.T, input string, r object.Representation, expected interface{}) {
 	switch exp := expected.(type) {
 	case nil:
@@ -213,6 +521,10 @@ func testEvaluatedObject(t *testing.T, input string, r object.Representation, ex
 		testIntegerObject(t, input, r, int64(exp))
 	case bool:
 		testBooleanObject(t, input, r, exp)
+	case float64:
+		testFloatObject(t, input, r, exp)
+	case string:
+		testStringObject(t, input, r, exp)
 	case *object.Error:
 		testErrorObject(t, input, r, exp)
 	}
@@ -253,9 +565,249 @@ func testBooleanObject(t *testing.T, input string, r object.Representation, expe
 	}
 }
 
+func testFloatObject(t *testing.T, input string, r object.Representation, expected float64) {
+	result, ok := r.(*object.Float)
+	if !ok {
+		t.Fatalf("%s\n\texpected *object.Float. got=%T (%+v)", input, r, r)
+	}
+
+	if math.Abs(result.Value-expected) > 1e-9 {
+		t.Fatalf("%s\n\texpected %g. got=%g", input, expected, result.Value)
+	}
+}
+
+func testStringObject(t *testing.T, input string, r object.Representation, expected string) {
+	result, ok := r.(*object.String)
+	if !ok {
+		t.Fatalf("%s\n\texpected *object.String. got=%T (%+v)", input, r, r)
+	}
+
+	if result.Value != expected {
+		t.Fatalf("%s\n\texpected %q. got=%q", input, expected, result.Value)
+	}
+}
+
 func testNullObject(t *testing.T, input string, r object.Representation) {
 	_, ok := r.(*object.Null)
 	if !ok {
 		t.Fatalf("%s\n\texpected *object.Null. got=%T (%+v)", input, r, r)
 	}
 }
+
+func TestTryCatchFinally(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`try { 1 / 0 } catch (e) { e.message }`, "division by zero: 1 / 0"},
+		{`try { missing } catch (e) { e.kind }`, "NameError"},
+		{`try { throw "bad input"; } catch (e) { e.message }`, "bad input"},
+		{`try { throw 42; } catch (e) { e.value + 1 }`, 43},
+		{`try { throw [1, 2]; } catch (e) { e.kind + ": " + e.message }`, "User: [1, 2]"},
+		{`try { 1 } catch (e) { 2 }`, 1},
+		{`let e = 5; try { 1 + true } catch (e) { 0 }; e;`, 5},
+		{`let f = fn() { throw "inner"; }; let g = fn() { 1 + f() }; try { g() } catch (e) { join(e.stack, ",") }`, "f,g"},
+		{`let f = fn() { 1 / 0 }; try { f() } catch (e) { join(e.stack, ",") }`, "f"},
+		{`let f = fn() { try { return 1; } finally { throw "ignored"; } }; try { f() } catch (e) { e.message }`, "ignored"},
+		{`let f = fn() { try { return 1; } finally { 2 } }; f();`, 1},
+		{`let f = fn() { try { 1 + true } catch (e) { return 2; } finally { 3 } }; f();`, 2},
+		{`let a = try { 1 + true } catch (e) { e }; a.message;`, "type mismatch: INTEGER + BOOLEAN"},
+		{`try { try { throw "inner"; } catch (e) { throw e; } } catch (e) { e.message }`, "inner"},
+		{`try { try { throw "a"; } finally { 1 } } catch (e) { e.message }`, "a"},
+		{`reduce(map([1, 0], fn(x) { try { 10 / x } catch (e) { -1 } }), fn(a, b) { a + b });`, 9},
+		{`throw "uncaught";`, &object.Error{Message: "uncaught"}},
+		{`try { 1 } catch (e) { 2 }; e;`, &object.Error{Message: "identifier not found: e"}},
+		{`try { throw 1; } catch (e) { e.file }`, &object.Error{Message: "type ERROR_VALUE has no field or method file"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestTryDoesNotCatchLimits(t *testing.T) {
+	const input = `let loop = fn(n) { loop(n + 1) }; try { loop(0) } catch (e) { "caught" }`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	evaluated := eval.New(eval.WithStepLimit(1000)).Eval(p.ParseProgram(), object.NewEnv())
+	err, ok := evaluated.(*object.Error)
+	if !ok || !errors.Is(err, eval.ErrStepLimitExceeded) {
+		t.Fatalf("expected the step limit error. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected object.ErrorKind
+	}{
+		{`1 + true;`, object.TypeError},
+		{`missing;`, object.NameError},
+		{`fn(x) { x }();`, object.ArityError},
+		{`[1][5];`, object.IndexError},
+		{`1 / 0;`, object.ZeroDivision},
+		{`sqrt(-1);`, object.ValueError},
+		{`read_file("go.mod");`, object.IOError},
+		{`import "missing.al" as m;`, object.ImportError},
+		{`let f = fn(n) { 1 + f(n) }; f(0);`, object.LimitError},
+		{`throw "bad";`, object.User},
+		{`throw error("IndexError", "custom");`, object.IndexError},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		err, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Fatalf("%s\n\texpected *object.Error. got=%s", tt.input, evaluated.Inspect())
+		}
+
+		if err.Kind != tt.expected {
+			t.Errorf("%s\n\texpected kind %s. got=%s", tt.input, tt.expected, err.Kind)
+		}
+
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("%s\n\texpected errors.Is to match %s", tt.input, tt.expected)
+		}
+	}
+}
+
+func TestErrorPositionAndCause(t *testing.T) {
+	const input = `let check = fn(x) {
+  if (x > 1) {
+    x / 0
+  } else { x }
+};
+try { check(2) } catch (e) { throw error("User", "check failed", e); }`
+
+	evaluated := testEval(input)
+	err, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected *object.Error. got=%s", evaluated.Inspect())
+	}
+
+	if err.Kind != object.User || err.Message != "check failed" {
+		t.Fatalf("expected the User error. got=%s", err.Inspect())
+	}
+
+	if err.Pos.String() != "6:30" {
+		t.Fatalf("expected the error at 6:30. got=%s", err.Pos)
+	}
+
+	if err.Cause == nil || err.Cause.Kind != object.ZeroDivision || err.Cause.Pos.String() != "3:5" {
+		t.Fatalf("expected a ZeroDivision cause at 3:5. got=%+v", err.Cause)
+	}
+
+	if !errors.Is(err, object.ZeroDivision) {
+		t.Fatalf("expected errors.Is to follow the cause")
+	}
+
+	const expected = "ERROR: User: check failed (at 6:30)\n" +
+		"caused by: ZeroDivision: division by zero: 2 / 0 (at 3:5)\n\tat check"
+	if err.Inspect() != expected {
+		t.Fatalf("expected %q. got=%q", expected, err.Inspect())
+	}
+}
+
+func TestRethrownCause(t *testing.T) {
+	const input = `try { try { 1 / 0 } catch (e1) { throw "wrapped"; } } catch (e2) { throw e2.cause; }`
+
+	evaluated := testEval(input)
+	err, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected *object.Error. got=%s", evaluated.Inspect())
+	}
+
+	// the cause rethrown is not caused by the error it caused
+	if err.Kind != object.ZeroDivision || err.Cause != nil {
+		t.Fatalf("expected the ZeroDivision error without cause. got=%s", err.Inspect())
+	}
+
+	if !errors.Is(err, object.ZeroDivision) || errors.Is(err, object.User) {
+		t.Fatalf("expected errors.Is to match only the ZeroDivision error")
+	}
+
+	// the error raised in the catch gets a cause without changing the caught error
+	const shared = `let caught = try { throw "first"; } catch (e) { e };
+let second = try { try { throw "second"; } catch (e) { throw caught; } } catch (e) { e };
+[caught.cause, second.cause.message];`
+
+	evaluated = testEval(shared)
+	if evaluated.Inspect() != "[null, second]" {
+		t.Fatalf("expected [null, second]. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestErrorBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`is_error(error("TypeError", "x"));`, true},
+		{`is_error("x");`, false},
+		{`is_error(try { 1 / 0 } catch (e) { e });`, true},
+		{`error("ValueError", "bad").kind;`, "ValueError"},
+		{`error("User", "outer", error("NameError", "inner")).cause.message;`, "inner"},
+		{`error("User", "x").cause;`, nil},
+		{`try { 1 + true } catch (e) { e.line * 100 + e.column }`, 107},
+		{`try { try { 1 / 0 } catch (e) { missing } } catch (e) { e.cause.kind }`, "ZeroDivision"},
+		{`error("Oops", "x");`, &object.Error{Message: "error: unknown error kind Oops"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+func TestResolvedScopes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// a let in a branch not taken leaves the slot unset and
+		// the name is found in the outer scope, as with the maps
+		{`let x = 1; let f = fn(c) { if (c) { let x = 2; }; x }; f(false) + f(true);`, 3},
+		{`let x = 1; let f = fn() { let y = x; let x = 10; x + y }; f();`, 11},
+		{`let counter = fn() { let n = 0; fn(step) { n + step } }; counter()(5);`, 5},
+		{`let f = fn(n) { if (n == 0) { 0 } else { n + f(n - 1) } }; let g = f; let f = 0; g(4);`, 10},
+		{`let f = fn() { try { throw 5; } catch (e) { let v = e.value; fn() { v + e.value } } }; f()();`, 10},
+		{`let f = fn(a, a) { a }; f(1, 2);`, 2},
+		{`let f = fn() { try { 1 / 0 } catch (e) { missing } }; f();`, &object.Error{Message: "identifier not found: missing"}},
+	}
+
+	for _, tt := range tests {
+		testEvalPaths(t, tt.input, tt.expected)
+	}
+}
+
+// BenchmarkEnv compares the bindings kept in the slots of frames,
+// when the program is resolved, with the bindings kept in maps
+func BenchmarkEnv(b *testing.B) {
+	const input = `
+let fib = fn(n) {
+	let a = n - 1;
+	let b = n - 2;
+	if (n < 2) { n } else { fib(a) + fib(b) }
+};
+fib(18);`
+
+	for _, resolve := range []bool{false, true} {
+		name := "map"
+		if resolve {
+			name = "slots"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			program := parser.New(lexer.New(input)).ParseProgram()
+			if resolve {
+				resolver.Resolve(program)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				eval.Eval(program, object.NewEnv())
+			}
+		})
+	}
+}
@@ -0,0 +1,555 @@
+package eval
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+var (
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	representationType = reflect.TypeOf((*object.Representation)(nil)).Elem()
+)
+
+// ToObject converts a Go value to the language representation. nil becomes
+// null, booleans, integers and floats of any size, strings, slices and maps
+// become their objects, converting the elements recursively, and values that
+// already are an object.Representation are kept as is. Structs, pointers and funcs are wrapped
+// in an object.HostValue so scripts can reach their fields and methods
+func ToObject(value interface{}) (object.Representation, error) {
+	if rep, ok := value.(object.Representation); ok {
+		return rep, nil
+	}
+
+	return toObject(reflect.ValueOf(value))
+}
+
+func toObject(v reflect.Value) (object.Representation, error) {
+	return convert(v, make(map[visit]bool))
+}
+
+// visit identifies a Go slice or map by its data, the same
+// backing array with another length is another slice
+type visit struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// convert converts the value, visiting holds the slices and maps
+// being converted to find the ones that contain themselves
+func convert(v reflect.Value, visiting map[visit]bool) (object.Representation, error) {
+	if !v.IsValid() {
+		return Null, nil
+	}
+
+	if v.Type().Implements(representationType) && v.CanInterface() {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return Null, nil
+		}
+
+		return v.Interface().(object.Representation), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return nativeBoolToBoolean(v.Bool()), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: v.Int()}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > uint64(1<<63-1) {
+			return nil, fmt.Errorf("%d overflows INTEGER", v.Uint())
+		}
+
+		return &object.Integer{Value: int64(v.Uint())}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return &object.Float{Value: v.Float()}, nil
+
+	case reflect.String:
+		return &object.String{Value: v.String()}, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && !v.IsNil() {
+			key := visit{ptr: v.Pointer(), len: v.Len(), typ: v.Type()}
+			if visiting[key] {
+				return nil, fmt.Errorf("cyclic structure: %s contains itself", v.Type())
+			}
+
+			visiting[key] = true
+			defer delete(visiting, key)
+		}
+
+		elements := make([]object.Representation, v.Len())
+		for idx := range elements {
+			element, err := convert(v.Index(idx), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", idx, err)
+			}
+
+			elements[idx] = element
+		}
+
+		return &object.Array{Elements: elements}, nil
+
+	case reflect.Map:
+		if !v.IsNil() {
+			key := visit{ptr: v.Pointer(), typ: v.Type()}
+			if visiting[key] {
+				return nil, fmt.Errorf("cyclic structure: %s contains itself", v.Type())
+			}
+
+			visiting[key] = true
+			defer delete(visiting, key)
+		}
+
+		hash := object.NewHash()
+
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := convert(iter.Key(), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("map key: %w", err)
+			}
+
+			hashable, ok := key.(object.Hashable)
+			if !ok {
+				return nil, fmt.Errorf("unusable as hash key: %s", key.Type())
+			}
+
+			value, err := convert(iter.Value(), visiting)
+			if err != nil {
+				return nil, fmt.Errorf("map value of %s: %w", key.Inspect(), err)
+			}
+
+			hash.Set(hashable, value)
+		}
+
+		return hash, nil
+
+	case reflect.Interface:
+		return convert(v.Elem(), visiting)
+
+	case reflect.Ptr, reflect.Func:
+		if v.IsNil() {
+			return Null, nil
+		}
+
+		return &object.HostValue{Value: v}, nil
+
+	case reflect.Struct:
+		return &object.HostValue{Value: v}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported Go type %s", v.Type())
+	}
+}
+
+// FromObject converts the language representation to a Go value, integers
+// become int64, floats float64, booleans bool, strings string, null nil, arrays
+// []interface{} and hashes map[string]interface{} when all the keys are strings,
+// otherwise map[interface{}]interface{}. Host values are unwrapped and representations
+// without a Go counterpart, like functions, are returned as is
+func FromObject(rep object.Representation) interface{} {
+	switch r := rep.(type) {
+	case nil, *object.Null:
+		return nil
+	case *object.Integer:
+		return r.Value
+	case *object.Float:
+		return r.Value
+	case *object.Boolean:
+		return r.Value
+	case *object.String:
+		return r.Value
+	case *object.Array:
+		elements := make([]interface{}, len(r.Elements))
+		for idx, element := range r.Elements {
+			elements[idx] = FromObject(element)
+		}
+
+		return elements
+	case *object.Hash:
+		return hashFromObject(r)
+	case *object.HostValue:
+		if !r.Value.IsValid() || !r.Value.CanInterface() {
+			return nil
+		}
+
+		return r.Value.Interface()
+	default:
+		return rep
+	}
+}
+
+func hashFromObject(hash *object.Hash) interface{} {
+	pairs := hash.Ordered()
+
+	stringKeys := true
+	for _, pair := range pairs {
+		if _, ok := pair.Key.(*object.String); !ok {
+			stringKeys = false
+			break
+		}
+	}
+
+	if stringKeys {
+		m := make(map[string]interface{}, len(pairs))
+		for _, pair := range pairs {
+			m[pair.Key.(*object.String).Value] = FromObject(pair.Value)
+		}
+
+		return m
+	}
+
+	m := make(map[interface{}]interface{}, len(pairs))
+	for _, pair := range pairs {
+		m[FromObject(pair.Key)] = FromObject(pair.Value)
+	}
+
+	return m
+}
+
+// toValue converts the representation to a Go value of the given type,
+// used to pass arguments to host functions and to index host maps
+func toValue(rep object.Representation, typ reflect.Type) (reflect.Value, error) {
+	if host, ok := rep.(*object.HostValue); ok && host.Value.IsValid() {
+		if host.Value.Type().AssignableTo(typ) {
+			return host.Value, nil
+		}
+
+		return reflect.Value{}, mismatch(rep, typ)
+	}
+
+	if _, ok := rep.(*object.Null); ok {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Func, reflect.Map, reflect.Slice:
+			return reflect.Zero(typ), nil
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		if typ.NumMethod() == 0 {
+			native := FromObject(rep)
+			if native == nil {
+				return reflect.Zero(typ), nil
+			}
+
+			return reflect.ValueOf(native), nil
+		}
+
+		if reflect.TypeOf(rep).Implements(typ) {
+			return reflect.ValueOf(rep), nil
+		}
+
+	case reflect.Bool:
+		if b, ok := rep.(*object.Boolean); ok {
+			return reflect.ValueOf(b.Value).Convert(typ), nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := rep.(*object.Integer); ok {
+			value := reflect.New(typ).Elem()
+			if value.OverflowInt(i.Value) {
+				return reflect.Value{}, fmt.Errorf("%d overflows %s", i.Value, typ)
+			}
+
+			value.SetInt(i.Value)
+			return value, nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := rep.(*object.Integer); ok {
+			value := reflect.New(typ).Elem()
+			if i.Value < 0 || value.OverflowUint(uint64(i.Value)) {
+				return reflect.Value{}, fmt.Errorf("%d overflows %s", i.Value, typ)
+			}
+
+			value.SetUint(uint64(i.Value))
+			return value, nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if f, ok := toFloat(rep); ok {
+			return reflect.ValueOf(f).Convert(typ), nil
+		}
+
+	case reflect.String:
+		if s, ok := rep.(*object.String); ok {
+			return reflect.ValueOf(s.Value).Convert(typ), nil
+		}
+
+	case reflect.Slice:
+		if array, ok := rep.(*object.Array); ok {
+			slice := reflect.MakeSlice(typ, len(array.Elements), len(array.Elements))
+			for idx, element := range array.Elements {
+				value, err := toValue(element, typ.Elem())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("element %d: %w", idx, err)
+				}
+
+				slice.Index(idx).Set(value)
+			}
+
+			return slice, nil
+		}
+
+	case reflect.Map:
+		if hash, ok := rep.(*object.Hash); ok {
+			m := reflect.MakeMapWithSize(typ, len(hash.Pairs))
+			for _, pair := range hash.Ordered() {
+				key, err := toValue(pair.Key, typ.Key())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("map key: %w", err)
+				}
+
+				value, err := toValue(pair.Value, typ.Elem())
+				if err != nil {
+					return reflect.Value{}, fmt.Errorf("map value of %s: %w", pair.Key.Inspect(), err)
+				}
+
+				m.SetMapIndex(key, value)
+			}
+
+			return m, nil
+		}
+	}
+
+	return reflect.Value{}, mismatch(rep, typ)
+}
+
+func mismatch(rep object.Representation, typ reflect.Type) error {
+	return fmt.Errorf("cannot use %s as %s", typeName(rep), typ)
+}
+
+// typeName describes the representation type, including the Go type of host values
+func typeName(rep object.Representation) string {
+	if host, ok := rep.(*object.HostValue); ok && host.Value.IsValid() {
+		return fmt.Sprintf("%s(%s)", host.Type(), host.Value.Type())
+	}
+
+	return string(rep.Type())
+}
+
+// deref follows pointers and interfaces to the value they hold
+func deref(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+
+		v = v.Elem()
+	}
+
+	return v, true
+}
+
+// selectHost returns the exported method or field of the host value, or the
+// element of a map with string keys. Fields are converted with ToObject while
+// methods are returned as host values that can be called
+func selectHost(host *object.HostValue, name string) object.Representation {
+	if !host.Value.IsValid() {
+		return errorF(object.NameError, "type %s has no field or method %s", typeName(host), name)
+	}
+
+	if method := host.Value.MethodByName(name); method.IsValid() {
+		return &object.HostValue{Value: method}
+	}
+
+	base, ok := deref(host.Value)
+	if !ok {
+		return errorF(object.NameError, "nil %s has no field or method %s", typeName(host), name)
+	}
+
+	switch base.Kind() {
+	case reflect.Struct:
+		field, has := base.Type().FieldByName(name)
+		if !has || field.PkgPath != "" {
+			break
+		}
+
+		fieldValue, ok := fieldByIndex(base, field.Index)
+		if !ok {
+			return errorF(object.TypeError, "field %s is reached through a nil embedded pointer", name)
+		}
+
+		value, err := toObject(fieldValue)
+		if err != nil {
+			return errorF(object.TypeError, "field %s: %s", name, err)
+		}
+
+		return value
+
+	case reflect.Map:
+		if base.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		return indexHost(host, &object.String{Value: name})
+	}
+
+	return errorF(object.NameError, "type %s has no field or method %s", typeName(host), name)
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex returning false
+// instead of panicking when an embedded pointer is nil
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			var ok bool
+			if v, ok = deref(v); !ok {
+				return v, false
+			}
+		}
+
+		v = v.Field(x)
+	}
+
+	return v, true
+}
+
+// indexHost returns the element of a host slice, array, string or map
+func indexHost(host *object.HostValue, index object.Representation) object.Representation {
+	base, ok := deref(host.Value)
+	if !ok {
+		return errorF(object.TypeError, "index of nil %s", typeName(host))
+	}
+
+	switch base.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		idx, ok := index.(*object.Integer)
+		if !ok {
+			return errorF(object.TypeError, "index of %s must be an INTEGER, got=%s", typeName(host), index.Type())
+		}
+
+		if idx.Value < 0 || idx.Value >= int64(base.Len()) {
+			return errorF(object.IndexError, "index out of range: %d (len %d)", idx.Value, base.Len())
+		}
+
+		value, err := toObject(base.Index(int(idx.Value)))
+		if err != nil {
+			return errorF(object.TypeError, "index %d: %s", idx.Value, err)
+		}
+
+		return value
+
+	case reflect.Map:
+		key, err := toValue(index, base.Type().Key())
+		if err != nil {
+			return errorF(object.TypeError, "map key: %s", err)
+		}
+
+		element := base.MapIndex(key)
+		if !element.IsValid() {
+			return Null
+		}
+
+		value, err := toObject(element)
+		if err != nil {
+			return errorF(object.TypeError, "map value of %s: %s", index.Inspect(), err)
+		}
+
+		return value
+
+	default:
+		return errorF(object.TypeError, "index operator not supported: %s", typeName(host))
+	}
+}
+
+// callHost calls a host function converting the arguments to the types of its
+// parameters. When the last result of the function is an error and it is not nil
+// the call evaluates to an error that wraps it, other results are converted with
+// ToObject, no results becomes null and many results an array. A panic in the
+// host function is recovered and turned into an error
+func callHost(host *object.HostValue, args []object.Representation) (result object.Representation) {
+	fn := host.Value
+	if !fn.IsValid() || fn.Kind() != reflect.Func {
+		return errorF(object.TypeError, "not a function: %s", typeName(host))
+	}
+
+	if fn.IsNil() {
+		return errorF(object.TypeError, "call of nil %s", typeName(host))
+	}
+
+	typ := fn.Type()
+	if typ.IsVariadic() {
+		if len(args) < typ.NumIn()-1 {
+			return errorF(object.ArityError, "expected at least %d arguments. got=%d", typ.NumIn()-1, len(args))
+		}
+	} else if len(args) != typ.NumIn() {
+		return errorF(object.ArityError, "expected %d arguments. got=%d", typ.NumIn(), len(args))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for idx, arg := range args {
+		var paramType reflect.Type
+		if typ.IsVariadic() && idx >= typ.NumIn()-1 {
+			paramType = typ.In(typ.NumIn() - 1).Elem()
+		} else {
+			paramType = typ.In(idx)
+		}
+
+		value, err := toValue(arg, paramType)
+		if err != nil {
+			return errorF(object.TypeError, "argument %d: %s", idx+1, err)
+		}
+
+		in[idx] = value
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result = errorF(object.RuntimeError, "host function panicked: %v", r)
+		}
+	}()
+
+	out := fn.Call(in)
+
+	if n := typ.NumOut(); n > 0 && typ.Out(n-1).Implements(errorType) {
+		if errValue := out[n-1]; !errValue.IsNil() {
+			goErr := errValue.Interface().(error)
+
+			err := errorF(object.RuntimeError, "%s", goErr)
+			err.Err = goErr
+			return err
+		}
+
+		out = out[:n-1]
+	}
+
+	switch len(out) {
+	case 0:
+		return Null
+	case 1:
+		value, err := toObject(out[0])
+		if err != nil {
+			return errorF(object.TypeError, "result: %s", err)
+		}
+
+		return value
+	default:
+		results := make([]object.Representation, len(out))
+		for idx, o := range out {
+			value, err := toObject(o)
+			if err != nil {
+				return errorF(object.TypeError, "result %d: %s", idx+1, err)
+			}
+
+			results[idx] = value
+		}
+
+		return &object.Array{Elements: results}
+	}
+}
+
+func nativeBoolToBoolean(b bool) *object.Boolean {
+	if b {
+		return True
+	}
+
+	return False
+}
@@ -0,0 +1,170 @@
+package eval
+
+import (
+	"errors"
+	"math"
+
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+var (
+	// ErrStepLimitExceeded is the Err of the error returned
+	// when an evaluation goes beyond WithStepLimit
+	ErrStepLimitExceeded = errors.New("step limit exceeded")
+
+	// ErrMemoryLimitExceeded is the Err of the error returned
+	// when an evaluation goes beyond WithMemoryLimit
+	ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+)
+
+// approximate sizes, in bytes, used to account the memory
+// allocated by an evaluation, they are not meant to match the
+// Go runtime but to give a deterministic measure to sandbox scripts
+const (
+	integerSize   = 16
+	floatSize     = 16
+	functionSize  = 48
+	parameterSize = 8
+	envSize       = 48
+	bindingSize   = 32
+	stringSize    = 16
+	arraySize     = 24
+	elementSize   = 16
+	hashSize      = 48
+	hashPairSize  = 64
+	hostSize      = 24
+)
+
+// maxAllocation is the most bytes a builtin creates at once, the Go
+// runtime panics instead of failing with bigger sizes
+const maxAllocation = 1 << 40
+
+// Usage is the budget consumed by an Evaluator since it was created
+type Usage struct {
+	// Steps is the number of evaluated nodes
+	Steps int64
+
+	// Memory is the approximate number of bytes allocated for
+	// objects and environment bindings, memory is never given
+	// back so it grows even if the objects are not reachable anymore
+	Memory int64
+}
+
+// WithStepLimit makes the evaluation fail once it has evaluated more
+// than steps nodes, a value less or equal to zero disables the limit
+func WithStepLimit(steps int64) Option {
+	return func(e *Evaluator) {
+		e.stepLimit = steps
+	}
+}
+
+// WithMemoryLimit makes the evaluation fail once it has allocated more
+// than bytes, see Usage.Memory, a value less or equal to zero disables the limit
+func WithMemoryLimit(bytes int64) Option {
+	return func(e *Evaluator) {
+		e.memoryLimit = bytes
+	}
+}
+
+// Usage returns the budget consumed by all the evaluations
+// done by the Evaluator, including the ones that failed
+func (e *Evaluator) Usage() Usage {
+	return e.usage
+}
+
+func (e *Evaluator) step() *object.Error {
+	e.usage.Steps++
+
+	if e.stepLimit > 0 && e.usage.Steps > e.stepLimit {
+		err := errorF(object.LimitError, "step limit exceeded (%d)", e.stepLimit)
+		err.Err = ErrStepLimitExceeded
+		err.Stack = e.stackTrace()
+		return err
+	}
+
+	return nil
+}
+
+func (e *Evaluator) allocate(bytes int64) *object.Error {
+	e.usage.Memory += bytes
+
+	if e.memoryLimit > 0 && e.usage.Memory > e.memoryLimit {
+		return e.memoryLimitError()
+	}
+
+	return nil
+}
+
+// reserve checks, without accounting them, that the bytes of the result
+// the builtin name is about to create fit the memory limit. A negative
+// size is an overflow, see multiply. The result is accounted by apply
+// once it is created
+func (e *Evaluator) reserve(name string, bytes int64) *object.Error {
+	if bytes < 0 || bytes > maxAllocation {
+		return errorF(object.ValueError, "%s: result too large", name)
+	}
+
+	if e.memoryLimit > 0 && e.usage.Memory+bytes > e.memoryLimit {
+		return e.memoryLimitError()
+	}
+
+	return nil
+}
+
+func (e *Evaluator) memoryLimitError() *object.Error {
+	err := errorF(object.LimitError, "memory limit exceeded (%d bytes)", e.memoryLimit)
+	err.Err = ErrMemoryLimitExceeded
+	err.Stack = e.stackTrace()
+	return err
+}
+
+// multiply returns a * b for non negative values, or -1 when it overflows
+func multiply(a, b int64) int64 {
+	if a != 0 && b > math.MaxInt64/a {
+		return -1
+	}
+
+	return a * b
+}
+
+// add returns a + b for non negative values, or -1 when any
+// of them is negative or the sum overflows
+func add(a, b int64) int64 {
+	if a < 0 || b < 0 || a > math.MaxInt64-b {
+		return -1
+	}
+
+	return a + b
+}
+
+// allocateObject accounts the memory of a newly created representation and
+// returns it, or the memory limit error. Shared instances like True, False
+// and Null are not accounted
+func (e *Evaluator) allocateObject(rep object.Representation) object.Representation {
+	if err := e.allocate(sizeOf(rep)); err != nil {
+		return err
+	}
+
+	return rep
+}
+
+func sizeOf(rep object.Representation) int64 {
+	switch rep := rep.(type) {
+	case *object.Integer:
+		return integerSize
+	case *object.Float:
+		return floatSize
+	case *object.Function:
+		return functionSize + int64(len(rep.Parameters))*parameterSize
+	case *object.String:
+		return stringSize + int64(len(rep.Value))
+	case *object.Array:
+		return arraySize + int64(len(rep.Elements))*elementSize
+	case *object.Hash:
+		return hashSize + int64(len(rep.Pairs))*hashPairSize
+	case *object.HostValue:
+		return hostSize
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,255 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/optimize"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/resolver"
+)
+
+// WithModulePaths adds directories where imported modules are searched, after
+// the directory of the file that imports them. The modules can only be imported
+// from these directories, the ones given to WithFileAccess and the directory
+// of the file given to EvalFile or EvalProgram
+func WithModulePaths(paths ...string) Option {
+	return func(e *Evaluator) {
+		e.modulePaths = append(e.modulePaths, paths...)
+	}
+}
+
+// importFrame is a module being evaluated
+type importFrame struct {
+	file string // the absolute path of the module file
+	name string // the path as written in the source
+}
+
+// EvalFile parses and evaluates the file as the main module of the program,
+// the modules it imports are searched first relative to its directory
+func (e *Evaluator) EvalFile(ctx context.Context, path string, env *object.Env) object.Representation {
+	file, err := filepath.Abs(path)
+	if err != nil {
+		return errorF(object.IOError, "cannot evaluate %s: %s", path, err)
+	}
+
+	program, parseErr := e.parseFile(file, path)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	return e.EvalProgram(ctx, path, program, env)
+}
+
+// EvalProgram evaluates the program parsed from the file as the main module,
+// like EvalFile, when the caller parses the file itself, eg. to check it with
+// Prepare. A program not prepared works the same but binds all its names in
+// maps instead of slots
+func (e *Evaluator) EvalProgram(ctx context.Context, path string, program *ast.Program, env *object.Env) object.Representation {
+	previous := e.ctx
+	e.ctx = ctx
+	defer func() {
+		e.ctx = previous
+	}()
+
+	file, err := filepath.Abs(path)
+	if err != nil {
+		return errorF(object.IOError, "cannot evaluate %s: %s", path, err)
+	}
+
+	e.importing = append(e.importing, importFrame{file: file, name: path})
+	defer func() {
+		e.importing = e.importing[:len(e.importing)-1]
+	}()
+
+	markTailCalls(program)
+	return e.eval(program, env)
+}
+
+func (e *Evaluator) evalImportStatement(node *ast.ImportStatement, env *object.Env) object.Representation {
+	module := e.importModule(node.Path.Value)
+	if isError(module) {
+		return module
+	}
+
+	if err := e.allocate(bindingSize); err != nil {
+		return err
+	}
+
+	bind(env, node.Alias, module)
+	return nil
+}
+
+// importModule evaluates the module once in its own environment,
+// the next imports of the same file return the cached module
+func (e *Evaluator) importModule(name string) object.Representation {
+	file, err := e.resolveModule(name)
+	if err != nil {
+		return err
+	}
+
+	for idx, frame := range e.importing {
+		if frame.file != file {
+			continue
+		}
+
+		cycle := make([]string, 0, len(e.importing)-idx+1)
+		for _, f := range e.importing[idx:] {
+			cycle = append(cycle, f.name)
+		}
+
+		cycle = append(cycle, name)
+		return errorF(object.ImportError, "import cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	if module, cached := e.modules[file]; cached {
+		return module
+	}
+
+	program, parseErr := e.parseFile(file, name)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	e.importing = append(e.importing, importFrame{file: file, name: name})
+	defer func() {
+		e.importing = e.importing[:len(e.importing)-1]
+	}()
+
+	module := &object.Module{Path: name, Env: object.NewEnv()}
+	markTailCalls(program)
+	if evaluated := e.eval(program, module.Env); isError(evaluated) {
+		return evaluated
+	}
+
+	e.modules[file] = module
+	return module
+}
+
+// resolveModule returns the absolute path of the module file, relative paths are
+// searched in the directory of the importing file, or the working directory when
+// the import is not in a file, and then in the module paths. The files outside
+// the allowed directories, see WithModulePaths, are denied without reading them
+func (e *Evaluator) resolveModule(name string) (string, *object.Error) {
+	allowed := e.moduleRoots()
+
+	if filepath.IsAbs(name) {
+		file, ok := inside(allowed, name)
+		if !ok {
+			return "", importDenied(name)
+		}
+
+		return file, nil
+	}
+
+	dirs := make([]string, 0, len(e.modulePaths)+1)
+	if len(e.importing) > 0 {
+		dirs = append(dirs, filepath.Dir(e.importing[len(e.importing)-1].file))
+	} else {
+		dirs = append(dirs, ".")
+	}
+
+	dirs = append(dirs, e.modulePaths...)
+
+	denied := false
+	for _, dir := range dirs {
+		file, ok := inside(allowed, filepath.Join(dir, name))
+		if !ok {
+			denied = true
+			continue
+		}
+
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file, nil
+		}
+	}
+
+	if denied {
+		return "", importDenied(name)
+	}
+
+	return "", errorF(object.ImportError, "cannot find module %s in %s", name, strings.Join(dirs, ", "))
+}
+
+// importDenied is an ImportError whose Err is ErrFileAccessDenied
+func importDenied(name string) *object.Error {
+	denied := errorF(object.ImportError, "import: access to %s denied", name)
+	denied.Err = ErrFileAccessDenied
+	return denied
+}
+
+// moduleRoots returns the directories the modules can be imported from,
+// resolved: the module paths, the directories given to WithFileAccess and
+// the directory of the main file when the program is evaluated from a file
+func (e *Evaluator) moduleRoots() []string {
+	roots := append([]string{}, e.fileAccess...)
+
+	dirs := append([]string{}, e.modulePaths...)
+	if len(e.importing) > 0 {
+		dirs = append(dirs, filepath.Dir(e.importing[0].file))
+	}
+
+	for _, dir := range dirs {
+		if resolved, err := resolvePath(dir); err == nil {
+			roots = append(roots, resolved)
+		}
+	}
+
+	return roots
+}
+
+func (e *Evaluator) parseFile(file, name string) (*ast.Program, *object.Error) {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errorF(object.IOError, "cannot read module %s: %s", name, err)
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) != 0 {
+		messages := make([]string, len(errs))
+		for idx, err := range errs {
+			messages[idx] = err.Error()
+		}
+
+		return nil, errorF(object.ImportError, "cannot parse module %s: %s", name, strings.Join(messages, "; "))
+	}
+
+	// the problems the resolver finds are raised when the evaluation
+	// reaches them, it is run for the functions to bind names in slots
+	e.Prepare(program)
+	return program, nil
+}
+
+// Prepare optimizes, unless WithoutOptimizations is given, and resolves the
+// program as the Evaluator does with the files it parses, for the programs
+// parsed by the caller. It returns the diagnostics of the resolver, the
+// builtins are the only names the program does not need to declare
+func (e *Evaluator) Prepare(program *ast.Program) []resolver.Diagnostic {
+	if !e.noOptimizations {
+		optimize.Program(program)
+	}
+
+	return resolver.Resolve(program, BuiltinNames()...)
+}
+
+// selectModule returns the binding of the module, names
+// starting with `_` are private to the module
+func selectModule(module *object.Module, name string) object.Representation {
+	if strings.HasPrefix(name, "_") {
+		return errorF(object.NameError, "%s is not exported by module %s", name, module.Path)
+	}
+
+	value, has := module.Env.Get(name)
+	if !has {
+		return errorF(object.NameError, "module %s has no binding %s", module.Path, name)
+	}
+
+	return value
+}
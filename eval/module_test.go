@@ -0,0 +1,164 @@
+package eval_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("cannot create %s: %s", filepath.Dir(path), err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatalf("cannot write %s: %s", path, err)
+		}
+	}
+
+	return dir
+}
+
+func TestImportModules(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"main.al": `import "lib/math.al" as m;
+			import "lib/math.al" as again;
+			m.square(m.base) + again.loaded;`,
+		"lib/math.al": `import "counter.al" as c;
+			let base = 3;
+			let square = fn(x) { x * x };
+			let loaded = c.count;
+			let _hidden = 1;`,
+		"lib/counter.al":  `let count = 1;`,
+		"shared/greet.al": `let hello = "hello";`,
+		"uses_path.al":    `import "greet.al" as g; g.hello;`,
+		"private.al":      `import "lib/math.al" as m; m._hidden;`,
+		"missing.al":      `import "lib/math.al" as m; m.cube;`,
+		"cycle_a.al":      `import "cycle_b.al" as b; 1;`,
+		"cycle_b.al":      `import "cycle_a.al" as a; 1;`,
+		"broken.al":       `import "lib/broken.al" as b;`,
+		"lib/broken.al":   `let = 1;`,
+		"notfound.al":     `import "nowhere.al" as n;`,
+	})
+
+	tests := []struct {
+		file     string
+		expected interface{}
+	}{
+		{"main.al", 10},
+		{"uses_path.al", "hello"},
+		{"private.al", &object.Error{Message: "_hidden is not exported by module lib/math.al"}},
+		{"missing.al", &object.Error{Message: "module lib/math.al has no binding cube"}},
+		{"cycle_a.al", &object.Error{Message: "import cycle: " +
+			filepath.Join(dir, "cycle_a.al") + " -> cycle_b.al -> cycle_a.al"}},
+	}
+
+	for _, tt := range tests {
+		evaluator := eval.New(eval.WithModulePaths(filepath.Join(dir, "shared")))
+		evaluated := evaluator.EvalFile(context.Background(), filepath.Join(dir, tt.file), object.NewEnv())
+		testEvaluatedObject(t, tt.file, evaluated, tt.expected)
+	}
+
+	errorTests := []struct {
+		file   string
+		prefix string
+	}{
+		{"broken.al", "cannot parse module lib/broken.al: "},
+		{"notfound.al", "cannot find module nowhere.al in "},
+	}
+
+	for _, tt := range errorTests {
+		evaluated := eval.New().EvalFile(context.Background(), filepath.Join(dir, tt.file), object.NewEnv())
+
+		err, ok := evaluated.(*object.Error)
+		if !ok || !strings.HasPrefix(err.Message, tt.prefix) {
+			t.Fatalf("%s\n\texpected error starting with %q. got=%s", tt.file, tt.prefix, evaluated.Inspect())
+		}
+	}
+}
+
+func TestImportModuleOnce(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"state.al": `let value = [1];`,
+	})
+
+	const input = `import "state.al" as a; import "state.al" as b;`
+
+	l := lexer.New(strings.Replace(input, "state.al", filepath.Join(dir, "state.al"), 2))
+	p := parser.New(l)
+
+	evaluator := eval.New(eval.WithModulePaths(dir))
+	env := object.NewEnv()
+	if err, ok := evaluator.Eval(p.ParseProgram(), env).(*object.Error); ok {
+		t.Fatalf("unexpected error: %s", err.Inspect())
+	}
+
+	a, _ := env.Get("a")
+	b, _ := env.Get("b")
+	if a == nil || a != b {
+		t.Fatalf("expected the module to be evaluated once. got=%v and %v", a, b)
+	}
+}
+
+func TestImportAccess(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"secret.txt":          `secret_token_abc`,
+		"scripts/main.al":     `import "../secret.txt" as s;`,
+		"scripts/lib.al":      `let value = 1;`,
+		"scripts/uses_lib.al": `import "lib.al" as l; l.value;`,
+	})
+
+	secret := filepath.Join(dir, "secret.txt")
+
+	tests := []struct {
+		name     string
+		evaluate func() object.Representation
+	}{
+		{"absolute import", func() object.Representation {
+			program := parser.New(lexer.New(`import "` + secret + `" as s;`)).ParseProgram()
+			return eval.New().Eval(program, object.NewEnv())
+		}},
+		{"relative import", func() object.Representation {
+			program := parser.New(lexer.New(`import "secret.txt" as s;`)).ParseProgram()
+			return eval.New(eval.WithModulePaths(filepath.Join(dir, "scripts"))).Eval(program, object.NewEnv())
+		}},
+		{"import out of the main file directory", func() object.Representation {
+			return eval.New().EvalFile(context.Background(), filepath.Join(dir, "scripts", "main.al"), object.NewEnv())
+		}},
+	}
+
+	for _, tt := range tests {
+		evaluated := tt.evaluate()
+
+		err, ok := evaluated.(*object.Error)
+		if !ok || !errors.Is(err.Err, eval.ErrFileAccessDenied) || err.Kind != object.ImportError {
+			t.Fatalf("%s: expected an import error denying the access. got=%s", tt.name, evaluated.Inspect())
+		}
+
+		if strings.Contains(err.Message, "secret_token_abc") {
+			t.Fatalf("%s: the error leaks the file content: %s", tt.name, err.Message)
+		}
+	}
+
+	evaluated := eval.New().EvalFile(context.Background(), filepath.Join(dir, "scripts", "uses_lib.al"), object.NewEnv())
+	testEvaluatedObject(t, "uses_lib.al", evaluated, 1)
+
+	program := parser.New(lexer.New(`import "` + secret + `" as s;`)).ParseProgram()
+	evaluated = eval.New(eval.WithFileAccess(dir)).Eval(program, object.NewEnv())
+	if err, ok := evaluated.(*object.Error); !ok || errors.Is(err.Err, eval.ErrFileAccessDenied) {
+		t.Fatalf("expected the allowed file to be imported. got=%s", evaluated.Inspect())
+	}
+}
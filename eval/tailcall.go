@@ -0,0 +1,76 @@
+package eval
+
+import (
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+const TAIL_CALL_OBJ object.Type = "TAIL_CALL"
+
+var _ object.Representation = (*tailCall)(nil)
+
+// tailCall is what a call expression in tail position evaluates to,
+// instead of recursing into the callee it goes back to the applyFunction
+// that is running the current function, which calls it in a loop
+// (a trampoline) without growing the Go stack
+type tailCall struct {
+	name      string
+	function  *object.Function
+	arguments []object.Representation
+}
+
+func (tc *tailCall) Type() object.Type {
+	return TAIL_CALL_OBJ
+}
+
+func (tc *tailCall) Inspect() string {
+	return "tail call to " + tc.name
+}
+
+// markTailCalls sets Tail on the calls in tail position of the function
+// bodies of the node, the evaluation returns them as a tailCall. It runs
+// when an evaluation starts, marking a node again changes nothing
+func markTailCalls(program ast.Node) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		if fn, ok := node.(*ast.FunctionLiteral); ok {
+			markTailBlock(fn.Body, true)
+		}
+
+		return true
+	})
+}
+
+// markTailBlock marks the calls directly returned by `return` statements,
+// those are always in tail position as the return leaves the function, and
+// when the block value is the function result (tail) the call that is the
+// last expression of the block
+func markTailBlock(block *ast.BlockStatement, tail bool) {
+	for idx, stmt := range block.Statements {
+		switch stmt := stmt.(type) {
+		case *ast.ReturnStatement:
+			markTailExpression(stmt.Value, true)
+		case *ast.ExpressionStatement:
+			last := idx == len(block.Statements)-1
+			markTailExpression(stmt.Expression, tail && last)
+		}
+	}
+}
+
+func markTailExpression(exp ast.Expression, tail bool) {
+	switch exp := exp.(type) {
+	case *ast.CallExpression:
+		exp.Tail = tail
+
+	case *ast.IfExpression:
+		// even when the if is not in tail position its
+		// branches can have return statements
+		markTailBlock(exp.Consequence, tail)
+		if exp.Alternative != nil {
+			markTailBlock(exp.Alternative, tail)
+		}
+
+	case *ast.TryExpression:
+		// the calls inside a try are never in tail position,
+		// the try must wait for them to catch their errors
+	}
+}
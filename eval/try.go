@@ -0,0 +1,141 @@
+package eval
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+// evalThrowStatement raises the value as an error, throwing a caught
+// error raises it again keeping its original stack
+func (e *Evaluator) evalThrowStatement(node *ast.ThrowStatement, env *object.Env) object.Representation {
+	value := e.eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	// throwing an error value raises it, a caught error keeps
+	// its stack and position while a new one gets them here
+	if caught, ok := value.(*object.ErrorValue); ok {
+		if caught.Error.Stack == nil {
+			caught.Error.Stack = e.stackTrace()
+		}
+
+		return caught.Error
+	}
+
+	message := value.Inspect()
+	if str, ok := value.(*object.String); ok {
+		message = str.Value
+	}
+
+	return &object.Error{
+		Kind:    object.User,
+		Message: message,
+		Thrown:  value,
+		Stack:   e.stackTrace(),
+	}
+}
+
+// evalTryExpression evaluates the block and, if it fails, the catch
+// block with the error bound to its parameter. The finally block always
+// runs last, its result is discarded unless it fails or returns
+func (e *Evaluator) evalTryExpression(node *ast.TryExpression, env *object.Env) object.Representation {
+	result := e.eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil && catchable(err) {
+		catchEnv := newEnv(env, node.CatchScope)
+		bind(catchEnv, node.Param, &object.ErrorValue{Error: err})
+
+		result = e.eval(node.Catch, catchEnv)
+
+		// an error raised while handling another one is caused by it, unless
+		// it is one of the causes of that error, eg. `throw e.cause`. The
+		// raised error can be referenced by other error values so it is copied
+		if raised, ok := result.(*object.Error); ok && raised.Cause == nil && !causedBy(err, raised) {
+			caused := *raised
+			caused.Cause = err
+			result = &caused
+		}
+	}
+
+	if node.Finally == nil {
+		return result
+	}
+
+	switch finally := e.eval(node.Finally, env).(type) {
+	case *object.Error, *object.Return:
+		return finally
+	}
+
+	return result
+}
+
+// causedBy reports whether cause is the error or one of its causes
+func causedBy(err, cause *object.Error) bool {
+	for ; err != nil; err = err.Cause {
+		if err == cause {
+			return true
+		}
+	}
+
+	return false
+}
+
+// catchable reports whether the scripts can recover from the error,
+// the ones that stop the evaluation on behalf of the host, like the
+// cancellation or the exhausted budgets, cannot be caught
+func catchable(err *object.Error) bool {
+	return !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) &&
+		!errors.Is(err, ErrStepLimitExceeded) &&
+		!errors.Is(err, ErrMemoryLimitExceeded)
+}
+
+// selectError exposes the fields of an error value: message, kind, line and
+// column, stack with the innermost call first, cause, the error that led to
+// this one, and value, the thrown value
+func selectError(caught *object.ErrorValue, name string) object.Representation {
+	err := caught.Error
+
+	switch name {
+	case "message":
+		return &object.String{Value: err.Message}
+
+	case "kind":
+		return &object.String{Value: err.Kind.String()}
+
+	case "line":
+		return &object.Integer{Value: int64(err.Pos.Line)}
+
+	case "column":
+		return &object.Integer{Value: int64(err.Pos.Column)}
+
+	case "stack":
+		frames := make([]object.Representation, len(err.Stack))
+		for idx, frame := range err.Stack {
+			frames[idx] = &object.String{Value: frame}
+		}
+
+		return &object.Array{Elements: frames}
+
+	case "cause":
+		if err.Cause != nil {
+			return &object.ErrorValue{Error: err.Cause}
+		}
+
+		return Null
+
+	case "value":
+		if err.Thrown != nil {
+			return err.Thrown
+		}
+
+		return Null
+
+	default:
+		return errorF(object.NameError, "type %s has no field or method %s", caught.Type(), name)
+	}
+}
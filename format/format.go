@@ -0,0 +1,474 @@
+// Package format prints alang programs in the canonical style: one statement
+// per line, blocks indented with tabs, single spaces around the infix operators
+// and only the parentheses the precedence of the operators requires
+package format
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// Source formats the program, the comments are kept and so are the
+// blank lines between statements, collapsed to a single one. Blocks
+// with a single expression written on one line stay on one line.
+// Formatting an already formatted source returns it unchanged
+func Source(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		messages := make([]string, len(p.Errors()))
+		for idx, err := range p.Errors() {
+			messages[idx] = err.Error()
+		}
+
+		return nil, errors.New(strings.Join(messages, "\n"))
+	}
+
+	printer := newPrinter(string(src))
+	printer.program(program)
+	return printer.out.Bytes(), nil
+}
+
+// Node formats the node, that does not need to come from a source,
+// so there are no comments nor blank lines to keep
+func Node(node ast.Node) string {
+	printer := newPrinter("")
+
+	switch node := node.(type) {
+	case *ast.Program:
+		printer.program(node)
+	case ast.Statement:
+		printer.statement(node, false)
+	case ast.Expression:
+		printer.expression(node)
+	}
+
+	return printer.out.String()
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	// tokens are the tokens of the source, without the EOF, comments are
+	// the comments not printed yet and closing maps the position of each
+	// `{`, `[` and `(` to the position of the token that closes it
+	tokens   []token.Token
+	comments []lexer.Comment
+	closing  map[token.Position]token.Position
+
+	// lastLine is the source line of the last statement
+	// or comment printed, used to keep the blank lines
+	lastLine int
+}
+
+func newPrinter(src string) *printer {
+	p := &printer{closing: make(map[token.Position]token.Position)}
+
+	l := lexer.New(src)
+	var open []token.Position
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		p.tokens = append(p.tokens, tok)
+
+		switch tok.Type {
+		case token.LBRACE, token.LBRACKET, token.LPAREN:
+			open = append(open, tok.Pos)
+		case token.RBRACE, token.RBRACKET, token.RPAREN:
+			if len(open) > 0 {
+				p.closing[open[len(open)-1]] = tok.Pos
+				open = open[:len(open)-1]
+			}
+		}
+	}
+
+	p.comments = l.Comments()
+	return p
+}
+
+func (p *printer) program(program *ast.Program) {
+	p.statements(program.Statements, token.Position{}, false)
+
+	if p.out.Len() > 0 {
+		p.out.WriteByte('\n')
+	}
+}
+
+// statements prints the statements each in its own line with the comments
+// before end, the position of the closing brace of the block, or all the
+// remaining comments when end is not valid. In a block the last expression
+// statement is the block value and has no semicolon
+func (p *printer) statements(stmts []ast.Statement, end token.Position, inBlock bool) {
+	first := true
+
+	for idx, stmt := range stmts {
+		p.commentsBefore(stmt.Pos(), &first)
+		p.startLine(stmt.Pos().Line, &first)
+
+		p.statement(stmt, inBlock && idx == len(stmts)-1)
+
+		next := end
+		if idx+1 < len(stmts) {
+			next = stmts[idx+1].Pos()
+		}
+
+		if line := p.lastTokenLine(next); line > p.lastLine {
+			p.lastLine = line
+		}
+	}
+
+	p.commentsBefore(end, &first)
+}
+
+// commentsBefore prints the comments that come before the position, or all of
+// them when pos is not valid. A comment written after a token in the same line
+// stays at the end of the line being printed, the others get their own lines
+func (p *printer) commentsBefore(pos token.Position, first *bool) {
+	for len(p.comments) > 0 && (!pos.IsValid() || before(p.comments[0].Pos, pos)) {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+
+		if p.trailing(comment) && p.out.Len() > 0 {
+			p.out.WriteString(" " + comment.Text)
+		} else {
+			p.startLine(comment.Pos.Line, first)
+			p.out.WriteString(comment.Text)
+		}
+
+		p.lastLine = comment.Pos.Line
+	}
+}
+
+// startLine starts the line of a statement or comment that is in the
+// source line, with a blank line before when the source has one
+func (p *printer) startLine(line int, first *bool) {
+	if p.out.Len() > 0 {
+		if !*first && p.lastLine > 0 && line > p.lastLine+1 {
+			p.out.WriteByte('\n')
+		}
+
+		p.newline()
+	}
+
+	*first = false
+}
+
+func (p *printer) newline() {
+	p.out.WriteByte('\n')
+	p.out.WriteString(strings.Repeat("\t", p.indent))
+}
+
+// trailing reports whether the comment comes after a token in its line
+func (p *printer) trailing(comment lexer.Comment) bool {
+	idx := p.tokensBefore(comment.Pos)
+	return idx > 0 && p.tokens[idx-1].Pos.Line == comment.Pos.Line
+}
+
+// lastTokenLine returns the line of the last token before the position,
+// or of the last token of the source when pos is not valid
+func (p *printer) lastTokenLine(pos token.Position) int {
+	idx := len(p.tokens)
+	if pos.IsValid() {
+		idx = p.tokensBefore(pos)
+	}
+
+	if idx == 0 {
+		return 0
+	}
+
+	return p.tokens[idx-1].Pos.Line
+}
+
+// tokensBefore returns how many tokens start before the position
+func (p *printer) tokensBefore(pos token.Position) int {
+	return sort.Search(len(p.tokens), func(idx int) bool {
+		return !before(p.tokens[idx].Pos, pos)
+	})
+}
+
+func (p *printer) statement(stmt ast.Statement, blockValue bool) {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		p.out.WriteString("let " + stmt.Name.Value)
+		if stmt.Type != nil {
+			p.out.WriteString(": " + stmt.Type.String())
+		}
+		p.out.WriteString(" = ")
+		p.expression(stmt.Value)
+		p.out.WriteByte(';')
+
+	case *ast.ReturnStatement:
+		p.out.WriteString("return ")
+		p.expression(stmt.Value)
+		p.out.WriteByte(';')
+
+	case *ast.ThrowStatement:
+		p.out.WriteString("throw ")
+		p.expression(stmt.Value)
+		p.out.WriteByte(';')
+
+	case *ast.ImportStatement:
+		p.out.WriteString("import " + stmt.Path.String() + " as " + stmt.Alias.Value + ";")
+
+	case *ast.ExpressionStatement:
+		p.expression(stmt.Expression)
+		if !blockValue {
+			p.out.WriteByte(';')
+		}
+
+	case *ast.BlockStatement:
+		p.block(stmt)
+	}
+}
+
+func (p *printer) block(block *ast.BlockStatement) {
+	end, hasEnd := p.closing[block.Token.Pos]
+
+	if inline, ok := p.inlineBlock(block, end, hasEnd); ok {
+		p.out.WriteString(inline)
+		return
+	}
+
+	if len(block.Statements) == 0 && !p.hasCommentsBefore(end, hasEnd) {
+		p.out.WriteString("{}")
+		return
+	}
+
+	p.out.WriteByte('{')
+	p.indent++
+	p.statements(block.Statements, end, true)
+	p.indent--
+	p.newline()
+	p.out.WriteByte('}')
+}
+
+// inlineBlock formats the block in a single line when it was written in a
+// single line, has a single expression and no comments, eg. `fn(x) { x * 2 }`
+func (p *printer) inlineBlock(block *ast.BlockStatement, end token.Position, hasEnd bool) (string, bool) {
+	if !hasEnd || end.Line != block.Token.Pos.Line || len(block.Statements) != 1 {
+		return "", false
+	}
+
+	stmt, ok := block.Statements[0].(*ast.ExpressionStatement)
+	if !ok || p.hasCommentsBefore(end, hasEnd) {
+		return "", false
+	}
+
+	inner := &printer{tokens: p.tokens, closing: p.closing, indent: p.indent}
+	inner.expression(stmt.Expression)
+
+	if bytes.ContainsRune(inner.out.Bytes(), '\n') {
+		return "", false
+	}
+
+	return "{ " + inner.out.String() + " }", true
+}
+
+func (p *printer) hasCommentsBefore(end token.Position, hasEnd bool) bool {
+	return len(p.comments) > 0 && hasEnd && before(p.comments[0].Pos, end)
+}
+
+func (p *printer) expression(exp ast.Expression) {
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		p.out.WriteString(exp.Value)
+
+	case *ast.IntegerLiteral:
+		p.literal(exp.Token, strconv.FormatInt(exp.Value, 10))
+
+	case *ast.FloatLiteral:
+		value := strconv.FormatFloat(exp.Value, 'f', -1, 64)
+		if !strings.Contains(value, ".") {
+			value += ".0"
+		}
+
+		p.literal(exp.Token, value)
+
+	case *ast.BooleanLiteral:
+		p.literal(exp.Token, strconv.FormatBool(exp.Value))
+
+	case *ast.StringLiteral:
+		p.out.WriteString(exp.String())
+
+	case *ast.PrefixExpression:
+		p.out.WriteString(exp.Operator)
+		p.operand(exp.Right, precedenceOf(exp.Right) < parser.PREFIX)
+
+	case *ast.InfixExpression:
+		precedence := parser.Precedence(token.TokenType(exp.Operator))
+
+		// the operators are left associative, so an operand
+		// on the right with the same precedence needs parentheses
+		p.operand(exp.Left, precedenceOf(exp.Left) < precedence)
+		p.out.WriteString(" " + exp.Operator + " ")
+		p.operand(exp.Right, precedenceOf(exp.Right) <= precedence)
+
+	case *ast.CallExpression:
+		p.operand(exp.Function, precedenceOf(exp.Function) < parser.CALL)
+		p.expressionList(exp.Token.Pos, "(", ")", exp.Arguments)
+
+	case *ast.IndexExpression:
+		p.operand(exp.Left, precedenceOf(exp.Left) < parser.INDEX)
+		p.out.WriteByte('[')
+		p.expression(exp.Index)
+		p.out.WriteByte(']')
+
+	case *ast.SelectorExpression:
+		p.operand(exp.Left, precedenceOf(exp.Left) < parser.INDEX)
+		p.out.WriteString("." + exp.Selector.Value)
+
+	case *ast.ArrayLiteral:
+		p.expressionList(exp.Token.Pos, "[", "]", exp.Elements)
+
+	case *ast.HashLiteral:
+		keys := make([]ast.Expression, len(exp.Pairs))
+		for idx, pair := range exp.Pairs {
+			keys[idx] = pair.Key
+		}
+
+		p.list(exp.Token.Pos, "{", "}", keys, func(idx int) {
+			p.expression(exp.Pairs[idx].Key)
+			p.out.WriteString(": ")
+			p.expression(exp.Pairs[idx].Value)
+		})
+
+	case *ast.FunctionLiteral:
+		p.out.WriteString("fn(")
+		for idx, param := range exp.Parameters {
+			if idx > 0 {
+				p.out.WriteString(", ")
+			}
+
+			p.out.WriteString(param.Value)
+			if typ := exp.ParameterType(idx); typ != nil {
+				p.out.WriteString(": " + typ.String())
+			}
+		}
+		p.out.WriteString(") ")
+		if exp.Result != nil {
+			p.out.WriteString("-> " + exp.Result.String() + " ")
+		}
+		p.block(exp.Body)
+
+	case *ast.IfExpression:
+		p.out.WriteString("if (")
+		p.expression(exp.Condition)
+		p.out.WriteString(") ")
+		p.block(exp.Consequence)
+
+		if exp.Alternative != nil {
+			p.out.WriteString(" else ")
+			p.block(exp.Alternative)
+		}
+
+	case *ast.TryExpression:
+		p.out.WriteString("try ")
+		p.block(exp.Block)
+
+		if exp.Catch != nil {
+			p.out.WriteString(" catch (" + exp.Param.Value + ") ")
+			p.block(exp.Catch)
+		}
+
+		if exp.Finally != nil {
+			p.out.WriteString(" finally ")
+			p.block(exp.Finally)
+		}
+	}
+}
+
+// literal prints the literal as written in the source, the value
+// is printed for the literals built without a token, eg. by ast.Modify
+func (p *printer) literal(tok token.Token, value string) {
+	if tok.Literal == "" {
+		tok.Literal = value
+	}
+
+	p.out.WriteString(tok.Literal)
+}
+
+func (p *printer) operand(exp ast.Expression, parenthesize bool) {
+	if parenthesize {
+		p.out.WriteByte('(')
+	}
+
+	p.expression(exp)
+
+	if parenthesize {
+		p.out.WriteByte(')')
+	}
+}
+
+func (p *printer) expressionList(open token.Position, left, right string, exps []ast.Expression) {
+	p.list(open, left, right, exps, func(idx int) {
+		p.expression(exps[idx])
+	})
+}
+
+// list prints the items, starting with the given expressions, separated by
+// commas between the delimiters. When there are comments before the closing
+// delimiter each item gets its own line so the comments stay next to them
+func (p *printer) list(open token.Position, left, right string, starts []ast.Expression, item func(idx int)) {
+	p.out.WriteString(left)
+
+	end, hasEnd := p.closing[open]
+	if !p.hasCommentsBefore(end, hasEnd) {
+		for idx := range starts {
+			if idx > 0 {
+				p.out.WriteString(", ")
+			}
+
+			item(idx)
+		}
+
+		p.out.WriteString(right)
+		return
+	}
+
+	p.indent++
+	for idx, start := range starts {
+		// a comment on the line of the previous item stays there
+		first := true
+		p.commentsBefore(start.Pos(), &first)
+
+		p.newline()
+		item(idx)
+		if idx < len(starts)-1 {
+			p.out.WriteByte(',')
+		}
+	}
+
+	first := true
+	p.commentsBefore(end, &first)
+	p.indent--
+
+	p.newline()
+	p.out.WriteString(right)
+}
+
+// precedenceOf returns how tight the expression binds, the prefix and infix
+// expressions bind as their operators and the other expressions can be an
+// operand of any operator without parentheses
+func precedenceOf(exp ast.Expression) int {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		return parser.Precedence(token.TokenType(exp.Operator))
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	default:
+		return parser.INDEX + 1
+	}
+}
+
+// before reports whether the position a comes before b in the source
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
@@ -0,0 +1,114 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/format"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let  x=1+2*3;", "let x = 1 + 2 * 3;\n"},
+		{"(1 + 2) * 3;", "(1 + 2) * 3;\n"},
+		{"((1 + 2) + 3) - (4 - 5);", "1 + 2 + 3 - (4 - 5);\n"},
+		{"a * (b / c);", "a * (b / c);\n"},
+		{"(a < b) == (c > d);", "a < b == c > d;\n"},
+		{"-(a + b) * !(c);", "-(a + b) * !c;\n"},
+		{"(-f)(x)[0].y;", "(-f)(x)[0].y;\n"},
+		{"(a + b).c;", "(a + b).c;\n"},
+		{`import "lib.al"  as lib;`, "import \"lib.al\" as lib;\n"},
+		{`{"a":[1,2.50],true:"q\"n"}`, "{\"a\": [1, 2.50], true: \"q\\\"n\"};\n"},
+		{"let f = fn(a,b){a+b};", "let f = fn(a, b) { a + b };\n"},
+		{"let f = fn( ) { };", "let f = fn() {};\n"},
+		{"let n:int=1;", "let n: int = 1;\n"},
+		{"let f = fn(a:[int],b) ->  {string:int} {a};", "let f = fn(a: [int], b) -> {string: int} { a };\n"},
+		{
+			"let f = fn(x) {\n  let y = x * 2;\n  return y;\n};",
+			"let f = fn(x) {\n\tlet y = x * 2;\n\treturn y;\n};\n",
+		},
+		{
+			"if (x) { let y = 1; y } else { throw \"no\"; }",
+			"if (x) {\n\tlet y = 1;\n\ty\n} else {\n\tthrow \"no\";\n};\n",
+		},
+		{
+			"try { f() } catch (e) { e.message } finally { g() }",
+			"try { f() } catch (e) { e.message } finally { g() };\n",
+		},
+		{
+			"let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;",
+			"let a = 1;\n\nlet b = 2;\nlet c = 3;\n",
+		},
+		{
+			"// the answer\nlet a = 42; // forty two\n\n// done",
+			"// the answer\nlet a = 42; // forty two\n\n// done\n",
+		},
+		{
+			"let f = fn() { // starts\n\n  // leading\n  1   // the value\n  // last\n};",
+			"let f = fn() { // starts\n\t// leading\n\t1 // the value\n\t// last\n};\n",
+		},
+		{"let f = fn() {\n  // empty\n};", "let f = fn() {\n\t// empty\n};\n"},
+		{
+			"let y = [\n  1, // first\n  2 // second\n];",
+			"let y = [\n\t1, // first\n\t2 // second\n];\n",
+		},
+		{
+			"let h = {\n  // the name\n  \"name\": \"alang\",\n  \"age\": [3,\n 4] // years\n};",
+			"let h = {\n\t// the name\n\t\"name\": \"alang\",\n\t\"age\": [3, 4] // years\n};\n",
+		},
+		{
+			"f(a, // the a\n  g(b,\n    // the c\n    c));",
+			"f(\n\ta, // the a\n\tg(\n\t\tb,\n\t\t// the c\n\t\tc\n\t)\n);\n",
+		},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		formatted, err := format.Source([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("unexpected error formatting %q: %s", tt.input, err)
+		}
+
+		if string(formatted) != tt.expected {
+			t.Errorf("formatting %q\nexpected:\n%s\ngot:\n%s", tt.input, tt.expected, formatted)
+		}
+
+		again, err := format.Source(formatted)
+		if err != nil {
+			t.Fatalf("unexpected error formatting %q: %s", formatted, err)
+		}
+
+		if string(again) != string(formatted) {
+			t.Errorf("formatting is not idempotent\nfirst:\n%s\nsecond:\n%s", formatted, again)
+		}
+	}
+}
+
+func TestSourceError(t *testing.T) {
+	_, err := format.Source([]byte("let = 1;"))
+	if err == nil {
+		t.Fatalf("expected a parse error")
+	}
+}
+
+func TestNode(t *testing.T) {
+	// (1 - 2) - 3.0, built without tokens as ast.Modify does
+	node := &ast.InfixExpression{
+		Token:    token.Token{Type: token.MINUS, Literal: "-"},
+		Operator: "-",
+		Left: &ast.InfixExpression{
+			Operator: "-",
+			Left:     &ast.IntegerLiteral{Value: 1},
+			Right:    &ast.IntegerLiteral{Value: 2},
+		},
+		Right: &ast.FloatLiteral{Value: 3},
+	}
+
+	if got := format.Node(node); got != "1 - 2 - 3.0" {
+		t.Fatalf("expected %q, got %q", "1 - 2 - 3.0", got)
+	}
+}
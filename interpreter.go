@@ -0,0 +1,145 @@
+// Package alang is the API to embed the language in Go programs
+package alang
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+type Option func(*Interpreter)
+
+// WithEvalOptions configures the evaluator used by the
+// interpreter, eg. to limit the call depth or the steps
+func WithEvalOptions(opts ...eval.Option) Option {
+	return func(i *Interpreter) {
+		i.evalOpts = append(i.evalOpts, opts...)
+	}
+}
+
+// WithStdout sets where the scripts print, os.Stdout by default
+func WithStdout(w io.Writer) Option {
+	return WithEvalOptions(eval.WithStdout(w))
+}
+
+// WithStdin sets where the scripts read lines from, os.Stdin by default
+func WithStdin(r io.Reader) Option {
+	return WithEvalOptions(eval.WithStdin(r))
+}
+
+// WithFileAccess allows the scripts to read and write the files inside
+// the directories, by default the scripts cannot access any file
+func WithFileAccess(dirs ...string) Option {
+	return WithEvalOptions(eval.WithFileAccess(dirs...))
+}
+
+// ParseError holds all the errors found while parsing the source
+type ParseError struct {
+	Errors []error
+}
+
+func (pe *ParseError) Error() string {
+	messages := make([]string, len(pe.Errors))
+	for idx, err := range pe.Errors {
+		messages[idx] = err.Error()
+	}
+
+	return "parse error: " + strings.Join(messages, "; ")
+}
+
+// Interpreter runs alang sources keeping the bindings between runs, so
+// a host can define values with Set, run a script and then call the
+// functions the script declared. An Interpreter must not be used by
+// more than one goroutine at a time
+type Interpreter struct {
+	env       *object.Env
+	evaluator *eval.Evaluator
+	evalOpts  []eval.Option
+}
+
+func New(opts ...Option) *Interpreter {
+	i := &Interpreter{
+		env: object.NewEnv(),
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	i.evaluator = eval.New(i.evalOpts...)
+	return i
+}
+
+// Run parses and evaluates the source, the result is converted to a Go
+// value, see FromObject. Parser failures are returned as *ParseError and
+// runtime failures as *object.Error
+func (i *Interpreter) Run(ctx context.Context, src string) (interface{}, error) {
+	l := lexer.New(src)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, &ParseError{Errors: p.Errors()}
+	}
+
+	i.evaluator.Prepare(program)
+
+	return result(i.evaluator.EvalContext(ctx, program, i.env))
+}
+
+// Set binds the name to the Go value converted to the language
+// representation, see ToObject
+func (i *Interpreter) Set(name string, value interface{}) error {
+	rep, err := ToObject(value)
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", name, err)
+	}
+
+	i.env.Set(name, rep)
+	return nil
+}
+
+// Get returns the value bound to the name converted to a Go value
+func (i *Interpreter) Get(name string) (interface{}, bool) {
+	rep, has := i.env.Get(name)
+	if !has {
+		return nil, false
+	}
+
+	return FromObject(rep), true
+}
+
+// Call calls the function bound to fnName with the arguments converted
+// to the language representation and returns its result as a Go value
+func (i *Interpreter) Call(fnName string, args ...interface{}) (interface{}, error) {
+	fn, has := i.env.Get(fnName)
+	if !has {
+		return nil, fmt.Errorf("identifier not found: %s", fnName)
+	}
+
+	arguments := make([]object.Representation, len(args))
+	for idx, arg := range args {
+		rep, err := ToObject(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert argument %d of %s: %w", idx, fnName, err)
+		}
+
+		arguments[idx] = rep
+	}
+
+	return result(i.evaluator.Apply(context.Background(), fnName, fn, arguments))
+}
+
+func result(rep object.Representation) (interface{}, error) {
+	if err, ok := rep.(*object.Error); ok {
+		return nil, err
+	}
+
+	return FromObject(rep), nil
+}
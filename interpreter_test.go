@@ -0,0 +1,347 @@
+package alang_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang"
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/object"
+)
+
+func TestInterpreterRun(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`5 + 5;`, int64(10)},
+		{`1 < 2;`, true},
+		{`1.5 * 2;`, 3.0},
+		{`if (false) { 1 }`, nil},
+		{`let x = 1;`, nil},
+	}
+
+	for _, tt := range tests {
+		interpreter := alang.New()
+
+		got, err := interpreter.Run(context.Background(), tt.input)
+		if err != nil {
+			t.Fatalf("%s\n\tunexpected error: %s", tt.input, err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("%s\n\texpected %v (%T). got=%v (%T)", tt.input, tt.expected, tt.expected, got, got)
+		}
+	}
+}
+
+func TestInterpreterErrors(t *testing.T) {
+	interpreter := alang.New()
+
+	_, err := interpreter.Run(context.Background(), `let = 5;`)
+	var parseErr *alang.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected *alang.ParseError. got=%T (%v)", err, err)
+	}
+
+	_, err = interpreter.Run(context.Background(), `5 + true;`)
+	var runtimeErr *object.Error
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("expected *object.Error. got=%T (%v)", err, err)
+	}
+
+	const expectedMessage = "type mismatch: INTEGER + BOOLEAN"
+	if runtimeErr.Message != expectedMessage {
+		t.Fatalf("expected %q. got=%q", expectedMessage, runtimeErr.Message)
+	}
+
+	if !errors.Is(err, object.TypeError) || runtimeErr.Pos.String() != "1:1" {
+		t.Fatalf("expected a TypeError at 1:1. got=%s", runtimeErr.Inspect())
+	}
+
+	_, err = interpreter.Run(context.Background(), `throw error("ValueError", "invalid", error("IOError", "unreachable"));`)
+	if !errors.Is(err, object.ValueError) || !errors.Is(err, object.IOError) || errors.Is(err, object.TypeError) {
+		t.Fatalf("expected a ValueError caused by an IOError. got=%v", err)
+	}
+
+	limited := alang.New(alang.WithEvalOptions(eval.WithStepLimit(100)))
+	_, err = limited.Run(context.Background(), `let loop = fn(n) { loop(n + 1) }; loop(0);`)
+	if !errors.Is(err, eval.ErrStepLimitExceeded) {
+		t.Fatalf("expected eval.ErrStepLimitExceeded. got=%v", err)
+	}
+}
+
+func TestInterpreterSetGetCall(t *testing.T) {
+	interpreter := alang.New()
+
+	if err := interpreter.Set("limit", uint8(100)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("strict", true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("invalid", complex(1, 2)); err == nil {
+		t.Fatalf("expected error setting a complex128")
+	}
+
+	const rules = `
+	let allowed = fn(amount) {
+		if (strict) {
+			return amount < limit;
+		}
+		true;
+	};
+	let total = limit * 2;
+	`
+
+	if _, err := interpreter.Run(context.Background(), rules); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	total, has := interpreter.Get("total")
+	if !has || total != int64(200) {
+		t.Fatalf("expected total 200. got=%v (has=%t)", total, has)
+	}
+
+	if _, has := interpreter.Get("unknown"); has {
+		t.Fatalf("expected unknown to not be bound")
+	}
+
+	allowed, err := interpreter.Call("allowed", 150)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if allowed != false {
+		t.Fatalf("expected false. got=%v", allowed)
+	}
+
+	if _, err := interpreter.Call("allowed"); err == nil || err.Error() != "expected 1 arguments. got=0" {
+		t.Fatalf("expected arity error. got=%v", err)
+	}
+
+	if _, err := interpreter.Call("total", 1); err == nil || err.Error() != "not a function: INTEGER" {
+		t.Fatalf("expected not a function error. got=%v", err)
+	}
+
+	if _, err := interpreter.Call("missing"); err == nil {
+		t.Fatalf("expected error calling a missing function")
+	}
+}
+
+func TestInterpreterIO(t *testing.T) {
+	var out strings.Builder
+	interpreter := alang.New(alang.WithStdout(&out))
+
+	if _, err := interpreter.Run(context.Background(), `println("total:", 1 + 2);`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if out.String() != "total: 3\n" {
+		t.Fatalf("expected output %q. got=%q", "total: 3\n", out.String())
+	}
+
+	_, err := interpreter.Run(context.Background(), `read_file("interpreter.go");`)
+	if !errors.Is(err, eval.ErrFileAccessDenied) {
+		t.Fatalf("expected eval.ErrFileAccessDenied. got=%v", err)
+	}
+
+	allowed := alang.New(alang.WithFileAccess("."))
+	content, err := allowed.Run(context.Background(), `read_file("go.mod");`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !strings.HasPrefix(content.(string), "module ") {
+		t.Fatalf("expected the go.mod content. got=%q", content)
+	}
+}
+
+type address struct {
+	City string
+}
+
+type customer struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Limits  map[string]int
+	Address *address
+	secret  string
+}
+
+func (c *customer) Greet(greeting string) string {
+	return greeting + ", " + c.Name
+}
+
+func (c *customer) Birthday() {
+	c.Age++
+}
+
+func (c *customer) Discount(percent int) (int, error) {
+	if percent > 100 {
+		return 0, errors.New("discount above 100%")
+	}
+
+	return percent * 2, nil
+}
+
+type holder struct {
+	Rep object.Representation
+}
+
+func TestInterpreterHostValues(t *testing.T) {
+	c := &customer{
+		Name:    "ana",
+		Age:     30,
+		Tags:    []string{"vip", "new"},
+		Limits:  map[string]int{"daily": 10},
+		Address: &address{City: "recife"},
+		secret:  "hidden",
+	}
+
+	interpreter := alang.New()
+	if err := interpreter.Set("customer", c); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("sum", func(values ...int) int {
+		total := 0
+		for _, v := range values {
+			total += v
+		}
+		return total
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("explode", func() { panic("boom") }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("names", []string{"a", "b"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interpreter.Set("scores", map[string]int{"a": 1}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`customer.Name;`, "ana"},
+		{`customer.Age + 1;`, int64(31)},
+		{`customer.Tags[0];`, "vip"},
+		{`customer.Limits["daily"];`, int64(10)},
+		{`customer.Limits["weekly"];`, nil},
+		{`customer.Address.City;`, "recife"},
+		{`customer.Greet("hi");`, "hi, ana"},
+		{`customer.Birthday(); customer.Age;`, int64(31)},
+		{`customer.Discount(10);`, int64(20)},
+		{`sum(1, 2, 3);`, int64(6)},
+		{`sum();`, int64(0)},
+		{`names[1];`, "b"},
+		{`scores["a"];`, int64(1)},
+	}
+
+	for _, tt := range tests {
+		got, err := interpreter.Run(context.Background(), tt.input)
+		if err != nil {
+			t.Fatalf("%s\n\tunexpected error: %s", tt.input, err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("%s\n\texpected %v (%T). got=%v (%T)", tt.input, tt.expected, tt.expected, got, got)
+		}
+	}
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{`customer.secret;`, "type HOST(*alang_test.customer) has no field or method secret"},
+		{`customer.Missing;`, "type HOST(*alang_test.customer) has no field or method Missing"},
+		{`customer.Greet(1);`, "argument 1: cannot use INTEGER as string"},
+		{`customer.Greet();`, "expected 1 arguments. got=0"},
+		{`customer.Discount(200);`, "discount above 100%"},
+		{`customer.Tags[5];`, "index out of range: 5 (len 2)"},
+		{`customer.Tags["a"];`, "array index must be an INTEGER, got=STRING"},
+		{`sum(true);`, "argument 1: cannot use BOOLEAN as int"},
+		{`explode();`, "host function panicked: boom"},
+		{`customer(1);`, "not a function: HOST(*alang_test.customer)"},
+	}
+
+	for _, tt := range errorTests {
+		_, err := interpreter.Run(context.Background(), tt.input)
+		if err == nil || err.Error() != tt.expected {
+			t.Fatalf("%s\n\texpected error %q. got=%v", tt.input, tt.expected, err)
+		}
+	}
+
+	if c.Age != 31 {
+		t.Fatalf("expected the script to change the host value. got age=%d", c.Age)
+	}
+}
+
+func TestInterpreterConversions(t *testing.T) {
+	interpreter := alang.New()
+
+	const script = `let config = {"name": "rules", "weights": [1, 2, 3], "enabled": true};`
+	if _, err := interpreter.Run(context.Background(), script); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	config, _ := interpreter.Get("config")
+	expected := map[string]interface{}{
+		"name":    "rules",
+		"weights": []interface{}{int64(1), int64(2), int64(3)},
+		"enabled": true,
+	}
+
+	if !reflect.DeepEqual(config, expected) {
+		t.Fatalf("expected %v. got=%v", expected, config)
+	}
+
+	if err := interpreter.Set("invalid", map[string]complex128{"a": 1}); err == nil {
+		t.Fatalf("expected error setting a map of complex128")
+	}
+
+	list := []interface{}{1, nil}
+	list[1] = list
+	hash := map[string]interface{}{"name": "cycle"}
+	hash["self"] = hash
+
+	for _, cyclic := range []interface{}{list, hash} {
+		err := interpreter.Set("cyclic", cyclic)
+		if err == nil || !strings.Contains(err.Error(), "cyclic structure") {
+			t.Fatalf("expected a cyclic structure error. got=%v", err)
+		}
+	}
+
+	// nil representations in interfaces are null
+	rep, err := alang.ToObject([]object.Representation{nil})
+	if err != nil || rep.Inspect() != "[null]" {
+		t.Fatalf("expected [null]. got=%v, %v", rep, err)
+	}
+
+	if err := interpreter.Set("holder", &holder{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if value, err := interpreter.Run(context.Background(), `holder.Rep;`); err != nil || value != nil {
+		t.Fatalf("expected nil. got=%v, %v", value, err)
+	}
+
+	// the same slice twice is not a cycle
+	shared := []interface{}{1}
+	if err := interpreter.Set("shared", []interface{}{shared, shared}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
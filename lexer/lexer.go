@@ -1,28 +1,52 @@
 package lexer
 
-import "github.com/EclesioMeloJunior/alang/token"
+import (
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/token"
+)
 
 type Lexer struct {
 	input        string
 	position     int
 	readPosition int
 	char         byte
+
+	// line and column are the position of char
+	line   int
+	column int
+
+	comments []Comment
+}
+
+// Comment is a `//` comment, it goes until the end of the line and
+// is skipped by NextToken. The text includes the leading slashes
+type Comment struct {
+	Pos  token.Position
+	Text string
 }
 
 func New(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{input: input, line: 1}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) readChar() {
+	if l.char == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
 	if l.readPosition >= len(l.input) {
 		l.char = 0
-		return
+	} else {
+		l.char = l.input[l.readPosition]
 	}
 
-	l.char = l.input[l.readPosition]
-
+	// the position also advances past the end of the input, so a
+	// word that ends the input is read until its last character
 	l.position = l.readPosition
 	l.readPosition += 1
 }
@@ -39,6 +63,8 @@ func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 	l.skipWhitespace()
 
+	pos := token.Position{Line: l.line, Column: l.column}
+
 	switch l.char {
 	case '=':
 		if l.peekChar() == '=' {
@@ -63,10 +89,35 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.LBRACE, l.char)
 	case '}':
 		tok = newToken(token.RBRACE, l.char)
+	case '[':
+		tok = newToken(token.LBRACKET, l.char)
+	case ']':
+		tok = newToken(token.RBRACKET, l.char)
+	case ':':
+		tok = newToken(token.COLON, l.char)
+	case '.':
+		tok = newToken(token.DOT, l.char)
+	case '"':
+		literal, ok := l.readString()
+		tok.Literal = literal
+		tok.Type = token.STRING
+
+		if !ok {
+			tok.Type = token.ILLEGAL
+		}
 	case '+':
 		tok = newToken(token.PLUS, l.char)
 	case '-':
-		tok = newToken(token.MINUS, l.char)
+		if l.peekChar() == '>' {
+			char := l.char
+			l.readChar()
+			tok = token.Token{
+				Type:    token.ARROW,
+				Literal: string(char) + string(l.char),
+			}
+		} else {
+			tok = newToken(token.MINUS, l.char)
+		}
 	case '!':
 		if l.peekChar() == '=' {
 			char := l.char
@@ -93,14 +144,22 @@ func (l *Lexer) NextToken() token.Token {
 		if isLetter(l.char) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupLiteralType(tok.Literal)
+			tok.Pos = pos
 
 			return tok
 		}
 
 		if isDigit(l.char) {
+			literal, isFloat := l.readNumber()
+
 			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Literal = literal
+
+			if isFloat {
+				tok.Type = token.FLOAT
+			}
 
+			tok.Pos = pos
 			return tok
 		}
 
@@ -108,6 +167,8 @@ func (l *Lexer) NextToken() token.Token {
 	}
 
 	l.readChar()
+
+	tok.Pos = pos
 	return tok
 }
 
@@ -116,9 +177,10 @@ func (l *Lexer) NextToken() token.Token {
 func (l *Lexer) readIdentifier() (ident string) {
 	identStarts := l.position
 
-	// read until the current character is not a letter
+	// read until the current character is not a letter or a digit, digits
+	// are allowed after the first letter, eg. `atan2`
 	// this allows things like: `let name="..."` or `let name = "..."`
-	for isLetter(l.char) {
+	for isLetter(l.char) || isDigit(l.char) {
 		l.readChar()
 	}
 
@@ -127,22 +189,93 @@ func (l *Lexer) readIdentifier() (ident string) {
 	return l.input[identStarts:identEnds]
 }
 
-func (l *Lexer) readNumber() string {
+// readString reads a double quoted string, the returned literal is the content
+// without the quotes and with the escape sequences \", \\, \n, \t and \r replaced.
+// If the input ends before the closing quote ok is false
+func (l *Lexer) readString() (literal string, ok bool) {
+	var out strings.Builder
+
+	for {
+		l.readChar()
+
+		switch l.char {
+		case '"':
+			return out.String(), true
+		case 0:
+			return out.String(), false
+		case '\\':
+			l.readChar()
+
+			switch l.char {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'r':
+				out.WriteByte('\r')
+			case 0:
+				return out.String(), false
+			default:
+				out.WriteByte(l.char)
+			}
+		default:
+			out.WriteByte(l.char)
+		}
+	}
+}
+
+// readNumber reads an integer or, when the digits are followed by
+// a dot and more digits, a float like `3.14`
+func (l *Lexer) readNumber() (literal string, isFloat bool) {
 	numberStarts := l.position
 
 	for isDigit(l.char) {
 		l.readChar()
 	}
 
+	if l.char == '.' && isDigit(l.peekChar()) {
+		isFloat = true
+		l.readChar()
+
+		for isDigit(l.char) {
+			l.readChar()
+		}
+	}
+
 	numberEnds := l.position
 
-	return l.input[numberStarts:numberEnds]
+	return l.input[numberStarts:numberEnds], isFloat
+}
+
+// Comments returns the comments skipped so far, in the source order
+func (l *Lexer) Comments() []Comment {
+	return l.comments
 }
 
+// skipWhitespace skips the whitespace and the comments
 func (l *Lexer) skipWhitespace() {
-	for isToIgnore(l.char) {
+	for {
+		switch {
+		case isToIgnore(l.char):
+			l.readChar()
+		case l.char == '/' && l.peekChar() == '/':
+			l.readComment()
+		default:
+			return
+		}
+	}
+}
+
+func (l *Lexer) readComment() {
+	comment := Comment{Pos: token.Position{Line: l.line, Column: l.column}}
+	commentStarts := l.position
+
+	for l.char != '\n' && l.char != 0 {
 		l.readChar()
 	}
+
+	comment.Text = strings.TrimRight(l.input[commentStarts:l.position], " \t\r")
+	l.comments = append(l.comments, comment)
 }
 
 func newToken(tokType token.TokenType, char byte) token.Token {
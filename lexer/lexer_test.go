@@ -8,7 +8,7 @@ import (
 )
 
 func Test_BasicTokens_NextToken(t *testing.T) {
-	input := "=+(){},;!-/*5<>"
+	input := "=+(){},;!-/*5<>->"
 
 	tests := []struct {
 		exepextedType   token.TokenType
@@ -29,6 +29,7 @@ func Test_BasicTokens_NextToken(t *testing.T) {
 		{token.INT, "5"},
 		{token.LT, "<"},
 		{token.GT, ">"},
+		{token.ARROW, "->"},
 	}
 
 	l := lexer.New(input)
@@ -150,3 +151,156 @@ if (5 < 10) {
 		}
 	}
 }
+
+func Test_CollectionTokens_NextToken(t *testing.T) {
+	const prog = `"foo bar";
+"say \"hi\"\n";
+[1, 2];
+{"key": user.name};
+"unterminated`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, "foo bar"},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "say \"hi\"\n"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACKET, "["},
+		{token.INT, "1"},
+		{token.COMMA, ","},
+		{token.INT, "2"},
+		{token.RBRACKET, "]"},
+		{token.SEMICOLON, ";"},
+		{token.LBRACE, "{"},
+		{token.STRING, "key"},
+		{token.COLON, ":"},
+		{token.IDENT, "user"},
+		{token.DOT, "."},
+		{token.IDENT, "name"},
+		{token.RBRACE, "}"},
+		{token.SEMICOLON, ";"},
+		{token.ILLEGAL, "unterminated"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(prog)
+
+	for idx, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong. expected=%q, got=%q",
+				idx, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token literal wrong. expected=%q, got=%q",
+				idx, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func Test_NumberTokens_NextToken(t *testing.T) {
+	const prog = `3.14 10 atan2(x1, 2.0) list.size 1.`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FLOAT, "3.14"},
+		{token.INT, "10"},
+		{token.IDENT, "atan2"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x1"},
+		{token.COMMA, ","},
+		{token.FLOAT, "2.0"},
+		{token.RPAREN, ")"},
+		{token.IDENT, "list"},
+		{token.DOT, "."},
+		{token.IDENT, "size"},
+		{token.INT, "1"},
+		{token.DOT, "."},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New(prog)
+
+	for idx, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong. expected=%q, got=%q",
+				idx, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token literal wrong. expected=%q, got=%q",
+				idx, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	const input = "let x = 10;\n  x + \"a\nb\" ;"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedPos     string
+	}{
+		{"let", "1:1"},
+		{"x", "1:5"},
+		{"=", "1:7"},
+		{"10", "1:9"},
+		{";", "1:11"},
+		{"x", "2:3"},
+		{"+", "2:5"},
+		{"a\nb", "2:7"},
+		{";", "3:4"},
+		{"", "3:5"},
+	}
+
+	l := lexer.New(input)
+
+	for idx, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral || tok.Pos.String() != tt.expectedPos {
+			t.Fatalf("tests[%d] - expected %q at %s. got=%q at %s",
+				idx, tt.expectedLiteral, tt.expectedPos, tok.Literal, tok.Pos)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	const input = "// leading\nlet x = 10 / 2; // trailing  \n//last"
+
+	expectedTypes := []token.TokenType{
+		token.LET, token.IDENT, token.ASSIGN, token.INT, token.SLASH, token.INT, token.SEMICOLON, token.EOF,
+	}
+
+	l := lexer.New(input)
+	for idx, expected := range expectedTypes {
+		if tok := l.NextToken(); tok.Type != expected {
+			t.Fatalf("tests[%d] - expected %s. got=%s", idx, expected, tok.Type)
+		}
+	}
+
+	expectedComments := []lexer.Comment{
+		{Pos: token.Position{Line: 1, Column: 1}, Text: "// leading"},
+		{Pos: token.Position{Line: 2, Column: 17}, Text: "// trailing"},
+		{Pos: token.Position{Line: 3, Column: 1}, Text: "//last"},
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expectedComments) {
+		t.Fatalf("expected %d comments. got=%d", len(expectedComments), len(comments))
+	}
+
+	for idx, expected := range expectedComments {
+		if comments[idx] != expected {
+			t.Fatalf("comments[%d] - expected %+v. got=%+v", idx, expected, comments[idx])
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package lint reports the suspicious constructs of alang programs, each
+// kind of problem is a rule with a stable ID that can be disabled
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/format"
+	"github.com/EclesioMeloJunior/alang/resolver"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// Rule is a kind of problem the linter reports
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules are all the rules, enabled unless they are disabled
+var Rules = []Rule{
+	{"undeclared", "names that are not declared"},
+	{"used-before-definition", "names used before the let declaring them runs"},
+	{"unused-variable", "lets in functions and catch blocks that are not used"},
+	{"unused-parameter", "function parameters that are not used"},
+	{"shadow", "declarations hiding a name of an outer scope"},
+	{"unreachable", "statements after a return or a throw"},
+	{"constant-condition", "if conditions that are literals"},
+	{"self-comparison", "comparisons of a value with itself"},
+	{"arity", "calls of known functions with the wrong number of arguments"},
+}
+
+// kinds are the rules reporting the diagnostics of the resolver
+var kinds = map[resolver.Kind]string{
+	resolver.Undeclared:           "undeclared",
+	resolver.UsedBeforeDefinition: "used-before-definition",
+	resolver.Unused:               "unused-variable",
+	resolver.UnusedParameter:      "unused-parameter",
+	resolver.Shadowed:             "shadow",
+}
+
+// Issue is a problem found in the program
+type Issue struct {
+	Rule    string
+	Pos     token.Position
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", i.Pos, i.Message, i.Rule)
+}
+
+// Lint checks the program with all the rules but the disabled ones,
+// the builtins are the only names the program does not declare.
+// The program is resolved, so it can be evaluated afterwards
+func Lint(program *ast.Program, disabled ...string) []Issue {
+	l := &linter{
+		enabled:   make(map[string]bool, len(Rules)),
+		functions: make(map[*ast.Identifier]*ast.FunctionLiteral),
+		info:      &resolver.Info{},
+	}
+
+	for _, rule := range Rules {
+		l.enabled[rule.ID] = true
+	}
+
+	for _, id := range disabled {
+		delete(l.enabled, id)
+	}
+
+	for _, diagnostic := range resolver.ResolveInfo(program, l.info, eval.BuiltinNames()...) {
+		l.report(kinds[diagnostic.Kind], diagnostic.Pos, diagnostic.Message())
+	}
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if let, ok := node.(*ast.LetStatement); ok {
+			if literal, ok := let.Value.(*ast.FunctionLiteral); ok {
+				l.functions[let.Name] = literal
+			}
+		}
+
+		return true
+	})
+
+	ast.Inspect(program, l.inspect)
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i].Pos, l.issues[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return l.issues
+}
+
+type linter struct {
+	enabled map[string]bool
+	info    *resolver.Info
+	issues  []Issue
+
+	// functions are the names declared by lets of function literals
+	functions map[*ast.Identifier]*ast.FunctionLiteral
+}
+
+func (l *linter) report(rule string, pos token.Position, message string) {
+	if l.enabled[rule] {
+		l.issues = append(l.issues, Issue{Rule: rule, Pos: pos, Message: message})
+	}
+}
+
+func (l *linter) inspect(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.Program:
+		l.unreachable(node.Statements)
+	case *ast.BlockStatement:
+		l.unreachable(node.Statements)
+
+	case *ast.IfExpression:
+		if constant(node.Condition) {
+			l.report("constant-condition", node.Condition.Pos(),
+				fmt.Sprintf("condition is always %s", format.Node(node.Condition)))
+		}
+
+	case *ast.InfixExpression:
+		switch node.Operator {
+		case "==", "!=", "<", ">":
+			left, right := format.Node(node.Left), format.Node(node.Right)
+			if pure(node.Left) && left == right {
+				l.report("self-comparison", node.Pos(),
+					fmt.Sprintf("%s compared with itself", left))
+			}
+		}
+
+	case *ast.CallExpression:
+		literal, name := l.callee(node.Function)
+		if literal != nil && len(literal.Parameters) != len(node.Arguments) {
+			l.report("arity", node.Pos(), fmt.Sprintf("%s expects %d arguments, called with %d",
+				name, len(literal.Parameters), len(node.Arguments)))
+		}
+	}
+
+	return true
+}
+
+// unreachable reports the first of the statements
+// that follows a return or a throw, if there is one
+func (l *linter) unreachable(stmts []ast.Statement) {
+	for idx := 0; idx+1 < len(stmts); idx++ {
+		switch stmts[idx].(type) {
+		case *ast.ReturnStatement, *ast.ThrowStatement:
+			l.report("unreachable", stmts[idx+1].Pos(), "unreachable code")
+			return
+		}
+	}
+}
+
+// callee returns the function literal the call evaluates, when it is known
+// without running the program, and how the function is called in messages
+func (l *linter) callee(function ast.Expression) (*ast.FunctionLiteral, string) {
+	switch function := function.(type) {
+	case *ast.FunctionLiteral:
+		return function, "function"
+	case *ast.Identifier:
+		// a name declared more than once can be bound to different values
+		decls := l.info.Uses[function]
+		if len(decls) == 1 {
+			if literal, ok := l.functions[decls[0]]; ok {
+				return literal, function.Value
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// constant reports whether the expression is a literal, or an operator applied to one
+func constant(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.BooleanLiteral, *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return constant(expr.Right)
+	default:
+		return false
+	}
+}
+
+// pure reports whether evaluating the expression twice gives the same value,
+// a call can give a different value each time and so can what it is part of
+func pure(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.Identifier, *ast.BooleanLiteral, *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral:
+		return true
+	case *ast.PrefixExpression:
+		return pure(expr.Right)
+	case *ast.InfixExpression:
+		return pure(expr.Left) && pure(expr.Right)
+	case *ast.IndexExpression:
+		return pure(expr.Left) && pure(expr.Index)
+	case *ast.SelectorExpression:
+		return pure(expr.Left)
+	default:
+		return false
+	}
+}
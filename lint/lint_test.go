@@ -0,0 +1,70 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/lint"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input    string
+		disabled []string
+		expected []string
+	}{
+		{`let f = fn(a) { a + abs(1) }; f(1);`, nil, nil},
+		{`missing;`, nil, []string{"1:1: identifier not found: missing (undeclared)"}},
+		{`let f = fn(a, b) { let c = 1; a }; f(1, 2);`, nil, []string{
+			"1:15: parameter b is not used (unused-parameter)",
+			"1:24: c declared and not used (unused-variable)",
+		}},
+		{`let f = fn(a, b) { let c = 1; a }; f(1, 2);`, []string{"unused-parameter", "unused-variable"}, nil},
+		{`let x = 1; let f = fn(x) { x }; f(x);`, nil, []string{"1:23: x shadows the declaration at 1:5 (shadow)"}},
+		{`let f = fn() { return 1; println(2); 3 }; f();`, nil, []string{"1:26: unreachable code (unreachable)"}},
+		{`throw "no"; 1;`, nil, []string{"1:13: unreachable code (unreachable)"}},
+		{`if (true) { 1 }; if (!0) { 2 };`, nil, []string{
+			"1:5: condition is always true (constant-condition)",
+			"1:22: condition is always !0 (constant-condition)",
+		}},
+		{`let a = [1]; a == a; a[0] != a[0]; a.b < a.b; a + a;`, nil, []string{
+			"1:14: a compared with itself (self-comparison)",
+			"1:22: a[0] compared with itself (self-comparison)",
+			"1:36: a.b compared with itself (self-comparison)",
+		}},
+		// the calls can give different values
+		{`rand() == rand();`, nil, nil},
+		{`let f = fn(a) { a }; f(); f(1, 2); fn() { 1 }(3);`, nil, []string{
+			"1:22: f expects 1 arguments, called with 0 (arity)",
+			"1:27: f expects 1 arguments, called with 2 (arity)",
+			"1:36: function expects 0 arguments, called with 1 (arity)",
+		}},
+		{`let f = fn(n) { if (n < 1) { 0 } else { f() } }; f(3);`, nil, []string{
+			"1:41: f expects 1 arguments, called with 0 (arity)",
+		}},
+		// the name can be bound to either function
+		{`let f = fn(a) { a }; if (abs(0)) { let f = fn() { 1 }; }; f();`, nil, nil},
+		{`let g = fn(h) { h() }; g(fn() { 1 });`, nil, nil},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%s: unexpected parser errors: %v", tt.input, p.Errors())
+		}
+
+		issues := lint.Lint(program, tt.disabled...)
+
+		got := make([]string, len(issues))
+		for idx, issue := range issues {
+			got[idx] = issue.String()
+		}
+
+		if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("%s\n\texpected %q. got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -1,21 +0,0 @@
-package main
-
-import (
-	"fmt"
-	"os"
-	"os/user"
-
-	"github.com/EclesioMeloJunior/alang/repl"
-)
-
-func main() {
-	user, err := user.Current()
-	if err != nil {
-		panic(err)
-	}
-
-	fmt.Printf("Hello %s! This is the alang!\n", user.Username)
-	fmt.Printf("Feel free to type in commands\n")
-
-	repl.Start(os.Stdin, os.Stdout)
-}
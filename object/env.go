@@ -12,21 +12,81 @@ func NewEnv() *Env {
 	}
 }
 
+// NewFrame creates the environment of a scope annotated by the resolver,
+// the bindings are kept in slots indexed as the names instead of a map
+func NewFrame(outer *Env, names []string) *Env {
+	return &Env{
+		outer: outer,
+		names: names,
+		slots: make([]Representation, len(names)),
+	}
+}
+
 type Env struct {
 	outer *Env
 	store map[string]Representation
+
+	// names and slots are the bindings of a frame, the names
+	// are shared and a slot is nil until its binding is set
+	names []string
+	slots []Representation
 }
 
+// Get looks the name up in the environment and then in the outer ones
 func (e *Env) Get(variable string) (rep Representation, has bool) {
-	rep, has = e.store[variable]
+	for env := e; env != nil; env = env.outer {
+		if rep, has = env.store[variable]; has {
+			return rep, true
+		}
 
-	if !has && e.outer != nil {
-		rep, has = e.outer.Get(variable)
+		for idx, name := range env.names {
+			if name == variable && env.slots[idx] != nil {
+				return env.slots[idx], true
+			}
+		}
 	}
 
-	return rep, has
+	return nil, false
+}
+
+// GetSlot returns the binding in the slot of the frame that is depth
+// levels out of the environment, has is false when it is not set yet
+func (e *Env) GetSlot(depth, slot int) (rep Representation, has bool) {
+	env := e
+	for ; depth > 0 && env != nil; depth-- {
+		env = env.outer
+	}
+
+	if env == nil || slot >= len(env.slots) {
+		return nil, false
+	}
+
+	rep = env.slots[slot]
+	return rep, rep != nil
 }
 
 func (e *Env) Set(name string, value Representation) {
+	for idx, slotName := range e.names {
+		if slotName == name {
+			e.slots[idx] = value
+			return
+		}
+	}
+
+	if e.store == nil {
+		e.store = make(map[string]Representation)
+	}
+
 	e.store[name] = value
 }
+
+// SetSlot binds the value to the slot of the frame, it
+// returns false when the environment has no such slot
+func (e *Env) SetSlot(slot int, value Representation) bool {
+	if slot >= len(e.slots) {
+		return false
+	}
+
+	e.slots[slot] = value
+	return true
+}
@@ -0,0 +1,65 @@
+package object
+
+import "strconv"
+
+// ErrorKind classifies the errors, the scripts see it as the `kind`
+// field of a caught error and the hosts can match it with errors.Is:
+//
+//	errors.Is(err, object.ZeroDivision)
+type ErrorKind int
+
+const (
+	// RuntimeError is the kind of the errors that fit no other kind
+	RuntimeError ErrorKind = iota
+	TypeError
+	NameError
+	ArityError
+	IndexError
+	ZeroDivision
+	ValueError
+	IOError
+	ImportError
+	LimitError
+	Cancelled
+	// User is the kind of the values given to throw
+	User
+)
+
+var errorKindNames = map[ErrorKind]string{
+	RuntimeError: "RuntimeError",
+	TypeError:    "TypeError",
+	NameError:    "NameError",
+	ArityError:   "ArityError",
+	IndexError:   "IndexError",
+	ZeroDivision: "ZeroDivision",
+	ValueError:   "ValueError",
+	IOError:      "IOError",
+	ImportError:  "ImportError",
+	LimitError:   "LimitError",
+	Cancelled:    "Cancelled",
+	User:         "User",
+}
+
+func (k ErrorKind) String() string {
+	if name, ok := errorKindNames[k]; ok {
+		return name
+	}
+
+	return "ErrorKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Error makes the kind a target for errors.Is
+func (k ErrorKind) Error() string {
+	return k.String()
+}
+
+// LookupErrorKind returns the kind with the name, eg. "TypeError"
+func LookupErrorKind(name string) (ErrorKind, bool) {
+	for kind, kindName := range errorKindNames {
+		if kindName == name {
+			return kind, true
+		}
+	}
+
+	return RuntimeError, false
+}
@@ -0,0 +1,28 @@
+package object
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// HostValue wraps a Go value exposed by the host program to the
+// scripts, its fields, methods and elements are reached by reflection
+type HostValue struct {
+	Value reflect.Value
+}
+
+func (h *HostValue) Type() Type {
+	return HOST_OBJ
+}
+
+func (h *HostValue) Inspect() string {
+	if !h.Value.IsValid() {
+		return "host(nil)"
+	}
+
+	if h.Value.Kind() == reflect.Func || !h.Value.CanInterface() {
+		return fmt.Sprintf("host(%s)", h.Value.Type())
+	}
+
+	return fmt.Sprintf("host(%v)", h.Value.Interface())
+}
@@ -2,28 +2,52 @@ package object
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
 )
 
 var (
 	_ Representation = (*Integer)(nil)
+	_ Representation = (*Float)(nil)
 	_ Representation = (*Boolean)(nil)
 	_ Representation = (*Null)(nil)
 	_ Representation = (*Error)(nil)
+	_ Representation = (*ErrorValue)(nil)
 	_ Representation = (*Function)(nil)
+	_ Representation = (*String)(nil)
+	_ Representation = (*Array)(nil)
+	_ Representation = (*Hash)(nil)
+	_ Representation = (*HostValue)(nil)
+	_ Representation = (*Module)(nil)
+	_ Representation = (*Builtin)(nil)
+
+	_ Hashable = (*Integer)(nil)
+	_ Hashable = (*Boolean)(nil)
+	_ Hashable = (*String)(nil)
+
+	_ error = (*Error)(nil)
 )
 
 type Type string
 
 const (
 	INTEGER_OBJ         Type = "INTEGER"
+	FLOAT_OBJ           Type = "FLOAT"
 	BOOLEAN_OBJ         Type = "BOOLEAN"
 	NULL_OBJ            Type = "NULL"
 	RETURN_VALUE_OBJECT Type = "RETURN_VALUE"
 	ERROR               Type = "ERROR"
+	ERROR_VALUE_OBJ     Type = "ERROR_VALUE"
 	FUNCTION_OBJ             = "FUNCTION_OBJ"
+	STRING_OBJ          Type = "STRING"
+	ARRAY_OBJ           Type = "ARRAY"
+	HASH_OBJ            Type = "HASH"
+	HOST_OBJ            Type = "HOST"
+	MODULE_OBJ          Type = "MODULE"
+	BUILTIN_OBJ         Type = "BUILTIN"
 )
 
 type Representation interface {
@@ -42,6 +66,28 @@ func (i *Integer) Type() Type {
 	return INTEGER_OBJ
 }
 
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: fmt.Sprintf("%d", i.Value)}
+}
+
+type Float struct {
+	Value float64
+}
+
+// Inspect prints the shortest representation that parses back to
+// the same value, integral values keep a `.0` to not look like integers
+func (f *Float) Inspect() string {
+	formatted := strconv.FormatFloat(f.Value, 'g', -1, 64)
+	if strings.ContainsAny(formatted, ".eIN") {
+		return formatted
+	}
+
+	return formatted + ".0"
+}
+func (f *Float) Type() Type {
+	return FLOAT_OBJ
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -53,6 +99,10 @@ func (b *Boolean) Type() Type {
 	return BOOLEAN_OBJ
 }
 
+func (b *Boolean) HashKey() HashKey {
+	return HashKey{Type: b.Type(), Value: fmt.Sprintf("%t", b.Value)}
+}
+
 type Null struct{}
 
 func (n *Null) Inspect() string {
@@ -74,21 +124,105 @@ func (r *Return) Inspect() string {
 }
 
 type Error struct {
+	Kind    ErrorKind
 	Message string
+
+	// Pos is where the error happened in the source, the zero
+	// position when it happened outside of a parsed source
+	Pos token.Position
+
+	// Stack holds the calls that were being evaluated when
+	// the error happened, the innermost call comes first
+	Stack []string
+
+	// Err is the Go error that aborted the evaluation, it is
+	// set when the host stops it, eg. by cancelling a context
+	Err error
+
+	// Cause is the error that led to this one, eg. the error
+	// being handled by the catch block that raised this one
+	Cause *Error
+
+	// Thrown is the value given to a throw statement,
+	// it is nil for the errors raised by the runtime
+	Thrown Representation
 }
 
 func (e *Error) Type() Type {
 	return ERROR
 }
 
+// Error makes the representation usable as a Go
+// error when surfaced to the host program
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the Go error that aborted the evaluation
+// or, when there is none, the cause of the error
+func (e *Error) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+
+	if e.Cause != nil {
+		return e.Cause
+	}
+
+	return nil
+}
+
+// Is reports whether the target is the kind of the error, so
+// errors.Is(err, object.TypeError) matches the type errors
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(ErrorKind)
+	return ok && kind == e.Kind
+}
+
 func (e *Error) Inspect() string {
-	return "ERROR: " + e.Message
+	var out strings.Builder
+	out.WriteString("ERROR: ")
+
+	for err := e; err != nil; err = err.Cause {
+		if err != e {
+			out.WriteString("\ncaused by: ")
+		}
+
+		out.WriteString(err.Kind.String() + ": " + err.Message)
+		if err.Pos.IsValid() {
+			out.WriteString(" (at " + err.Pos.String() + ")")
+		}
+
+		for _, frame := range err.Stack {
+			out.WriteString("\n\tat " + frame)
+		}
+	}
+
+	return out.String()
+}
+
+// ErrorValue holds an error as a regular value, eg. the one bound by a catch
+// clause, unlike an Error it does not stop the evaluation until it is thrown
+type ErrorValue struct {
+	Error *Error
+}
+
+func (ev *ErrorValue) Type() Type {
+	return ERROR_VALUE_OBJ
+}
+
+func (ev *ErrorValue) Inspect() string {
+	return ev.Error.Kind.String() + ": " + ev.Error.Message
 }
 
 type Function struct {
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Env
+
+	// Scope is the scope of the calls when the function was
+	// resolved, the calls then bind the names in slots
+	Scope *ast.Scope
 }
 
 func (f *Function) Type() Type {
@@ -103,3 +237,137 @@ func (f *Function) Inspect() string {
 
 	return fmt.Sprintf("fn(%s){...}", strings.Join(params, ", "))
 }
+
+type String struct {
+	Value string
+}
+
+func (s *String) Type() Type {
+	return STRING_OBJ
+}
+
+func (s *String) Inspect() string {
+	return s.Value
+}
+
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Value: s.Value}
+}
+
+type Array struct {
+	Elements []Representation
+}
+
+func (a *Array) Type() Type {
+	return ARRAY_OBJ
+}
+
+func (a *Array) Inspect() string {
+	elements := make([]string, len(a.Elements))
+	for i, element := range a.Elements {
+		elements[i] = element.Inspect()
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashKey identifies the value of a hash key, two
+// keys are equal when they have the same type and value
+type HashKey struct {
+	Type  Type
+	Value string
+}
+
+// Hashable is implemented by the representations
+// that can be used as keys of a hash
+type Hashable interface {
+	Representation
+	HashKey() HashKey
+}
+
+type HashPair struct {
+	Key   Representation
+	Value Representation
+}
+
+// Hash maps hashable keys to values keeping
+// the order the keys were first inserted
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	keys  []HashKey
+}
+
+func NewHash() *Hash {
+	return &Hash{
+		Pairs: make(map[HashKey]HashPair),
+	}
+}
+
+func (h *Hash) Type() Type {
+	return HASH_OBJ
+}
+
+func (h *Hash) Inspect() string {
+	pairs := make([]string, 0, len(h.keys))
+	for _, pair := range h.Ordered() {
+		pairs = append(pairs, pair.Key.Inspect()+": "+pair.Value.Inspect())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func (h *Hash) Set(key Hashable, value Representation) {
+	hashKey := key.HashKey()
+	if _, has := h.Pairs[hashKey]; !has {
+		h.keys = append(h.keys, hashKey)
+	}
+
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Get(key Hashable) (value Representation, has bool) {
+	pair, has := h.Pairs[key.HashKey()]
+	return pair.Value, has
+}
+
+// Ordered returns the pairs in insertion order
+func (h *Hash) Ordered() []HashPair {
+	pairs := make([]HashPair, len(h.keys))
+	for i, key := range h.keys {
+		pairs[i] = h.Pairs[key]
+	}
+
+	return pairs
+}
+
+// Module is a file evaluated in its own environment, the
+// bindings it declares are reached as `alias.name`
+type Module struct {
+	Path string // the path as written in the import statement
+	Env  *Env
+}
+
+func (m *Module) Type() Type {
+	return MODULE_OBJ
+}
+
+func (m *Module) Inspect() string {
+	return fmt.Sprintf("module(%s)", m.Path)
+}
+
+type BuiltinFunction func(args ...Representation) Representation
+
+// Builtin is a function implemented in Go and
+// available to the scripts without being declared
+type Builtin struct {
+	Name string
+	Fn   BuiltinFunction
+}
+
+func (b *Builtin) Type() Type {
+	return BUILTIN_OBJ
+}
+
+func (b *Builtin) Inspect() string {
+	return fmt.Sprintf("builtin(%s)", b.Name)
+}
@@ -0,0 +1,298 @@
+// Package optimize rewrites alang programs into simpler ones that evaluate
+// to the same values: the operators applied to literals are folded into the
+// literal they result in and the branches of the if expressions whose
+// condition is a boolean literal are removed when they cannot be taken
+package optimize
+
+import (
+	"math"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// Program optimizes the program in place. The operations that fail when
+// evaluated, like a division by zero, are kept so they still fail at runtime.
+// It runs before the resolver, the nodes it builds are not annotated
+func Program(program *ast.Program) {
+	ast.Modify(program, fold)
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.Program:
+			node.Statements = eliminate(node.Statements)
+		case *ast.BlockStatement:
+			node.Statements = eliminate(node.Statements)
+		}
+
+		return true
+	})
+}
+
+// fold replaces the node by a simpler one, the operands
+// of the node are already folded when it gets here
+func fold(node ast.Node) ast.Node {
+	switch node := node.(type) {
+	case *ast.PrefixExpression:
+		if folded := foldPrefix(node); folded != nil {
+			return folded
+		}
+
+	case *ast.InfixExpression:
+		if folded := foldInfix(node); folded != nil {
+			return folded
+		}
+
+	case *ast.IfExpression:
+		condition, ok := node.Condition.(*ast.BooleanLiteral)
+		if !ok {
+			return node
+		}
+
+		if !condition.Value {
+			node.Consequence = &ast.BlockStatement{Token: node.Consequence.Token}
+			if node.Alternative == nil {
+				return node
+			}
+
+			if expr := single(node.Alternative); expr != nil {
+				return expr
+			}
+
+			return node
+		}
+
+		node.Alternative = nil
+		if expr := single(node.Consequence); expr != nil {
+			return expr
+		}
+	}
+
+	return node
+}
+
+// single returns the expression of the block when it is its only statement
+func single(block *ast.BlockStatement) ast.Expression {
+	if len(block.Statements) != 1 {
+		return nil
+	}
+
+	if stmt, ok := block.Statements[0].(*ast.ExpressionStatement); ok {
+		return stmt.Expression
+	}
+
+	return nil
+}
+
+// eliminate replaces the if statements whose branch is known by the statements
+// of the branch, blocks do not create scopes so their lets bind the same names.
+// An if that does nothing is removed unless its null value is the last one
+func eliminate(stmts []ast.Statement) []ast.Statement {
+	var eliminated []ast.Statement
+
+	for idx, stmt := range stmts {
+		branch, ok := knownBranch(stmt)
+		if !ok {
+			if eliminated != nil {
+				eliminated = append(eliminated, stmt)
+			}
+			continue
+		}
+
+		if eliminated == nil {
+			eliminated = append([]ast.Statement{}, stmts[:idx]...)
+		}
+
+		if len(branch) == 0 && idx == len(stmts)-1 {
+			eliminated = append(eliminated, stmt)
+			continue
+		}
+
+		eliminated = append(eliminated, eliminate(branch)...)
+	}
+
+	if eliminated == nil {
+		return stmts
+	}
+
+	return eliminated
+}
+
+// knownBranch returns the statements of the branch the if statement takes,
+// ok is false when the statement is not an if or the branch is not known
+func knownBranch(stmt ast.Statement) (branch []ast.Statement, ok bool) {
+	expr, isExpr := stmt.(*ast.ExpressionStatement)
+	if !isExpr {
+		return nil, false
+	}
+
+	node, isIf := expr.Expression.(*ast.IfExpression)
+	if !isIf {
+		return nil, false
+	}
+
+	condition, isLiteral := node.Condition.(*ast.BooleanLiteral)
+	switch {
+	case !isLiteral:
+		return nil, false
+	case condition.Value:
+		return node.Consequence.Statements, true
+	case node.Alternative != nil:
+		return node.Alternative.Statements, true
+	default:
+		return nil, true
+	}
+}
+
+func foldPrefix(node *ast.PrefixExpression) ast.Expression {
+	switch node.Operator {
+	case token.BANG:
+		switch right := node.Right.(type) {
+		case *ast.BooleanLiteral:
+			return boolean(node.Pos(), !right.Value)
+		case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral:
+			return boolean(node.Pos(), false)
+		}
+
+	case token.MINUS:
+		switch right := node.Right.(type) {
+		case *ast.IntegerLiteral:
+			return integer(node.Pos(), -right.Value)
+		case *ast.FloatLiteral:
+			return float(node.Pos(), -right.Value)
+		}
+	}
+
+	return nil
+}
+
+// foldInfix returns the literal the infix expression evaluates to,
+// or nil when an operand is not a literal or the evaluation fails
+func foldInfix(node *ast.InfixExpression) ast.Expression {
+	pos := node.Pos()
+
+	switch left := node.Left.(type) {
+	case *ast.IntegerLiteral:
+		switch right := node.Right.(type) {
+		case *ast.IntegerLiteral:
+			return foldInteger(pos, node.Operator, left.Value, right.Value)
+		case *ast.FloatLiteral:
+			return foldFloat(pos, node.Operator, float64(left.Value), right.Value)
+		}
+
+	case *ast.FloatLiteral:
+		switch right := node.Right.(type) {
+		case *ast.IntegerLiteral:
+			return foldFloat(pos, node.Operator, left.Value, float64(right.Value))
+		case *ast.FloatLiteral:
+			return foldFloat(pos, node.Operator, left.Value, right.Value)
+		}
+
+	case *ast.BooleanLiteral:
+		if right, ok := node.Right.(*ast.BooleanLiteral); ok {
+			switch node.Operator {
+			case token.EQ:
+				return boolean(pos, left.Value == right.Value)
+			case token.NOT_EQ:
+				return boolean(pos, left.Value != right.Value)
+			}
+		}
+
+	case *ast.StringLiteral:
+		if right, ok := node.Right.(*ast.StringLiteral); ok {
+			switch node.Operator {
+			case token.PLUS:
+				return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Pos: pos}, Value: left.Value + right.Value}
+			case token.EQ:
+				return boolean(pos, left.Value == right.Value)
+			case token.NOT_EQ:
+				return boolean(pos, left.Value != right.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+func foldInteger(pos token.Position, op string, left, right int64) ast.Expression {
+	switch op {
+	case token.PLUS:
+		return integer(pos, left+right)
+	case token.MINUS:
+		return integer(pos, left-right)
+	case token.ASTHERISC:
+		return integer(pos, left*right)
+	case token.SLASH:
+		// the division by zero is raised at runtime
+		if right == 0 {
+			return nil
+		}
+		return integer(pos, left/right)
+	case token.LT:
+		return boolean(pos, left < right)
+	case token.GT:
+		return boolean(pos, left > right)
+	case token.EQ:
+		return boolean(pos, left == right)
+	case token.NOT_EQ:
+		return boolean(pos, left != right)
+	default:
+		return nil
+	}
+}
+
+func foldFloat(pos token.Position, op string, left, right float64) ast.Expression {
+	switch op {
+	case token.PLUS:
+		return float(pos, left+right)
+	case token.MINUS:
+		return float(pos, left-right)
+	case token.ASTHERISC:
+		return float(pos, left*right)
+	case token.SLASH:
+		if right == 0 {
+			return nil
+		}
+		return float(pos, left/right)
+	case token.LT:
+		return boolean(pos, left < right)
+	case token.GT:
+		return boolean(pos, left > right)
+	case token.EQ:
+		return boolean(pos, left == right)
+	case token.NOT_EQ:
+		return boolean(pos, left != right)
+	default:
+		return nil
+	}
+}
+
+// the literals keep the position of the expression they replace, the
+// token literal is left empty so they print the value and not the source
+
+func boolean(pos token.Position, value bool) ast.Expression {
+	tokenType := token.TokenType(token.FALSE)
+	if value {
+		tokenType = token.TRUE
+	}
+
+	return &ast.BooleanLiteral{Token: token.Token{Type: tokenType, Pos: pos}, Value: value}
+}
+
+func integer(pos token.Position, value int64) ast.Expression {
+	// the smallest integer cannot be written in the source
+	if value == math.MinInt64 {
+		return nil
+	}
+
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Pos: pos}, Value: value}
+}
+
+func float(pos token.Position, value float64) ast.Expression {
+	// there are no literals for the infinities nor NaN
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil
+	}
+
+	return &ast.FloatLiteral{Token: token.Token{Type: token.FLOAT, Pos: pos}, Value: value}
+}
@@ -0,0 +1,98 @@
+package optimize_test
+
+import (
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/eval"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/object"
+	"github.com/EclesioMeloJunior/alang/optimize"
+	"github.com/EclesioMeloJunior/alang/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("%s: unexpected parser errors: %v", input, p.Errors())
+	}
+
+	return program
+}
+
+func TestProgram(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`2 * (5 + 10);`, `30;`},
+		{`1 + 2 * x;`, `(1 + (2 * x));`},
+		{`x + 1 + 2;`, `((x + 1) + 2);`},
+		{`-(3 - 5); !true; !!5; -2.5 * 2;`, `2;false;true;-5.0;`},
+		{`1 < 2 == true; 1.5 > 1; "a" + "b" == "ab"; true != false;`, `true;true;true;true;`},
+		// the failures are left for the evaluation
+		{`10 / (5 - 5); 1.0 / 0; 1 + true; "a" - "b"; -"a"; [1] + 1;`, `(10 / 0);(1.0 / 0);(1 + true);("a" - "b");(-"a");([1] + 1);`},
+		{`let f = fn() { 4 / 2 };`, `let f = fn() { 2; };`},
+		{`if (1 < 2) { a } else { b };`, `a;`},
+		{`let v = if (1 > 2) { a } else { b };`, `let v = b;`},
+		{`let v = if (true) { let w = 1; w } else { b };`, `let v = if (true) { let w = 1; w; };`},
+		{`let v = if (false) { a } else { let w = 1; w };`, `let v = if (false) {} else { let w = 1; w; };`},
+		{`if (true) { let w = 1; if (!false) { w; 2 } }; 3;`, `let w = 1;w;2;3;`},
+		{`if (false) { a }; 3;`, `3;`},
+		// the value of the program is still null
+		{`3; if (false) { a };`, `3;if (false) {};`},
+		{`if (x) { 1 } else { 2 };`, `if (x) { 1; } else { 2; };`},
+		// the condition must be a boolean
+		{`if (1) { 2 };`, `if (1) { 2; };`},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		optimize.Program(program)
+
+		if program.String() != tt.expected {
+			t.Errorf("%s\n\texpected %q. got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestProgramEvaluation(t *testing.T) {
+	inputs := []string{
+		`2 * (5 + 10) - 3 / 2 + 0.5`,
+		`let f = fn(n) { if (1 < 2) { return n * 2; }; n }; f(3)`,
+		`let f = fn() { if (true) { let w = 2; }; w + 1 }; f()`,
+		`if (2 > 1) { "yes" } else { "no" }`,
+		`1; if (false) { 2 }`,
+		`let x = 1; if (true) { x + 1 } else { 0 }`,
+		`10 / (5 - 5)`,
+		`if (1) { 2 }`,
+		`try { 1 / 0 } catch (e) { e.message }`,
+	}
+
+	for _, input := range inputs {
+		expected := run(t, input, eval.WithoutOptimizations())
+		got := run(t, input)
+
+		if got != expected {
+			t.Errorf("%s\n\texpected %q. got=%q", input, expected, got)
+		}
+	}
+}
+
+func run(t *testing.T, input string, opts ...eval.Option) string {
+	t.Helper()
+
+	evaluator := eval.New(opts...)
+	program := parse(t, input)
+	evaluator.Prepare(program)
+
+	evaluated := evaluator.Eval(program, object.NewEnv())
+	if evaluated == nil {
+		return "<nil>"
+	}
+
+	return evaluated.Inspect()
+}
@@ -17,6 +17,7 @@ const (
 	PRODUCT      // *
 	PREFIX       // -X or !X
 	CALL         // myFunc(x)
+	INDEX        // array[index] or value.field
 )
 
 var precedences = map[token.TokenType]int{
@@ -29,10 +30,14 @@ var precedences = map[token.TokenType]int{
 	token.SLASH:     PRODUCT,
 	token.ASTHERISC: PRODUCT,
 	token.LPAREN:    CALL,
+	token.LBRACKET:  INDEX,
+	token.DOT:       INDEX,
 }
 
-func (p *Parser) peekPrecedence() int {
-	prec, ok := precedences[p.peekToken.Type]
+// Precedence returns how tight the infix operator of the token type
+// binds its operands, LOWEST when the token is not an infix operator
+func Precedence(t token.TokenType) int {
+	prec, ok := precedences[t]
 	if ok {
 		return prec
 	}
@@ -40,13 +45,12 @@ func (p *Parser) peekPrecedence() int {
 	return LOWEST
 }
 
-func (p *Parser) curPrecedence() int {
-	prec, ok := precedences[p.curToken.Type]
-	if ok {
-		return prec
-	}
+func (p *Parser) peekPrecedence() int {
+	return Precedence(p.peekToken.Type)
+}
 
-	return LOWEST
+func (p *Parser) curPrecedence() int {
+	return Precedence(p.curToken.Type)
 }
 
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
@@ -109,6 +113,22 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{
+		Token: p.curToken,
+	}
+
+	floatValue, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		p.errors = append(p.errors,
+			fmt.Errorf("cannot parse %s to float64", p.curToken.Literal))
+		return nil
+	}
+
+	lit.Value = floatValue
+	return lit
+}
+
 func (p *Parser) parseBooleanLiteral() ast.Expression {
 	return &ast.BooleanLiteral{
 		Token: p.curToken,
@@ -189,6 +209,51 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	// the catch clause binds the error to the name between the parentheses
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LPAREN) || !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		expression.Param = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(token.RPAREN) || !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		p.errors = append(p.errors,
+			fmt.Errorf("expected catch or finally after try block. got type %s", p.peekToken.Type))
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{
 		Token:      p.curToken,
@@ -216,7 +281,20 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
-	fnLiteral.Parameters = p.parseFunctionParameters()
+	fnLiteral.Parameters, fnLiteral.ParameterTypes = p.parseFunctionParameters()
+	if fnLiteral.Parameters == nil {
+		return nil
+	}
+
+	// the result type is optional, after an arrow
+	if p.peekTokenIs(token.ARROW) {
+		p.nextToken()
+		p.nextToken()
+
+		if fnLiteral.Result = p.parseType(); fnLiteral.Result == nil {
+			return nil
+		}
+	}
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
@@ -227,43 +305,55 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 	return fnLiteral
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+// parseFunctionParameters returns nil parameters when they are not valid,
+// the types are nil unless a parameter is annotated, see ast.FunctionLiteral
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []ast.TypeExpression) {
 	parameters := []*ast.Identifier{}
+	var types []ast.TypeExpression
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return parameters
+		return parameters, nil
 	}
 
 	p.nextToken()
 
-	identifier := &ast.Identifier{
-		Token: p.curToken,
-		Value: p.curToken.Literal,
-	}
+	for {
+		identifier := &ast.Identifier{
+			Token: p.curToken,
+			Value: p.curToken.Literal,
+		}
 
-	parameters = append(parameters, identifier)
+		typ, ok := p.parseTypeAnnotation()
+		if !ok {
+			return nil, nil
+		}
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // goes to comma token as it is the peek token
+		if typ != nil && types == nil {
+			types = make([]ast.TypeExpression, len(parameters), len(parameters)+1)
+		}
 
-		if !p.expectPeek(token.IDENT) { // after the comma must exists an identifier
-			return nil
+		parameters = append(parameters, identifier)
+		if types != nil {
+			types = append(types, typ)
 		}
 
-		identifier = &ast.Identifier{
-			Token: p.curToken,
-			Value: p.curToken.Literal,
+		if !p.peekTokenIs(token.COMMA) {
+			break
 		}
 
-		parameters = append(parameters, identifier)
+		p.nextToken() // goes to comma token as it is the peek token
+
+		if !p.expectPeek(token.IDENT) { // after the comma must exists an identifier
+			return nil, nil
+		}
 	}
 
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, nil
 	}
 
-	return parameters
+	return parameters, types
 }
 
 func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
@@ -276,9 +366,15 @@ func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseCallArguments() []ast.Expression {
+	return p.parseExpressionList(token.RPAREN)
+}
+
+// parseExpressionList parses expressions separated by comma until
+// the end token, eg. the arguments of a call or the elements of an array
+func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	args := []ast.Expression{}
 
-	if p.peekTokenIs(token.RPAREN) {
+	if p.peekTokenIs(end) {
 		p.nextToken()
 		return args
 	}
@@ -295,9 +391,92 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 		args = append(args, nextArgument)
 	}
 
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(end) {
 		return nil
 	}
 
 	return args
 }
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+}
+
+func (p *Parser) parseArrayLiteral() ast.Expression {
+	array := &ast.ArrayLiteral{
+		Token: p.curToken,
+	}
+
+	array.Elements = p.parseExpressionList(token.RBRACKET)
+	return array
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{
+		Token: p.curToken,
+		Pairs: []ast.HashPair{},
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		// a key must be followed by `:` and its value
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return hash
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expression := &ast.IndexExpression{
+		Token: p.curToken,
+		Left:  left,
+	}
+
+	p.nextToken()
+	expression.Index = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseSelectorExpression(left ast.Expression) ast.Expression {
+	expression := &ast.SelectorExpression{
+		Token: p.curToken,
+		Left:  left,
+	}
+
+	// after the `.` must exists the name of the field or method
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	expression.Selector = &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
+	return expression
+}
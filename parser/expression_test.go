@@ -117,6 +117,7 @@ func TestIdentifierExpression(t *testing.T) {
 
 	prog := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
 
 	if len(prog.Statements) != 1 {
 		t.Fatalf("program has not enough statements. got=%d", len(prog.Statements))
@@ -140,6 +141,7 @@ func TestIntegerLiteralExpression(t *testing.T) {
 
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, program)
 
 	if len(program.Statements) != 1 {
 		t.Fatalf("expected 1 statement. got=%d", len(program.Statements))
@@ -176,6 +178,7 @@ func TestBooleanLiteralExpression(t *testing.T) {
 		prog := p.ParseProgram()
 
 		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
 
 		if len(prog.Statements) != 1 {
 			t.Fatalf("expected 1 statement. got=%d", len(prog.Statements))
@@ -208,6 +211,7 @@ func TestParsingPrefixOperator(t *testing.T) {
 
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, program)
 
 		if len(program.Statements) != 1 {
 			t.Fatalf("expected 1 statement. got=%d", len(program.Statements))
@@ -249,6 +253,7 @@ func TestParsingInfixExpressions(t *testing.T) {
 		p := parser.New(l)
 		prog := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
 
 		if len(prog.Statements) != 1 {
 			t.Fatalf("expected 1 statement. got=%d", len(prog.Statements))
@@ -271,107 +276,123 @@ func TestParsingInfixExpression(t *testing.T) {
 	}{
 		{
 			"true;",
-			"true",
+			"true;",
 		},
 		{
 			"false;",
-			"false",
+			"false;",
 		},
 		{
 			"1 + (2 + 3) + 4;",
-			"((1 + (2 + 3)) + 4)",
+			"((1 + (2 + 3)) + 4);",
 		},
 		{
 			"(5 + 5) * 2;",
-			"((5 + 5) * 2)",
+			"((5 + 5) * 2);",
 		},
 		{
 			"2 / (5 + 5);",
-			"(2 / (5 + 5))",
+			"(2 / (5 + 5));",
 		},
 		{
 			"-(5 + 5);",
-			"(-(5 + 5))",
+			"(-(5 + 5));",
 		},
 		{
 			"!(true == true);",
-			"(!(true == true))",
+			"(!(true == true));",
 		},
 		{
 			"3 > 5 == false;",
-			"((3 > 5) == false)",
+			"((3 > 5) == false);",
 		},
 		{
 			"3 < 5 == true;",
-			"((3 < 5) == true)",
+			"((3 < 5) == true);",
 		},
 		{
 			"-a * b;",
-			"((-a) * b)",
+			"((-a) * b);",
 		},
 		{
 			"!-a;",
-			"(!(-a))",
+			"(!(-a));",
 		},
 		{
 			"a + b + c;",
-			"((a + b) + c)",
+			"((a + b) + c);",
 		},
 		{
 			"a + b - c;",
-			"((a + b) - c)",
+			"((a + b) - c);",
 		},
 		{
 			"a * b * c;",
-			"((a * b) * c)",
+			"((a * b) * c);",
 		},
 		{
 			"a * b / c;",
-			"((a * b) / c)",
+			"((a * b) / c);",
 		},
 		{
 			"a + b / c;",
-			"(a + (b / c))",
+			"(a + (b / c));",
 		},
 		{
 			"a + b * c + d / e - f;",
-			"(((a + (b * c)) + (d / e)) - f)",
+			"(((a + (b * c)) + (d / e)) - f);",
 		},
 		{
 			"5 + 2 * 3;",
-			"(5 + (2 * 3))",
+			"(5 + (2 * 3));",
 		},
 		{
 			"3 + 4; -5 * 5;",
-			"(3 + 4)((-5) * 5)",
+			"(3 + 4);((-5) * 5);",
 		},
 		{
 			"5 > 4 == 3 < 4;",
-			"((5 > 4) == (3 < 4))",
+			"((5 > 4) == (3 < 4));",
 		},
 		{
 			"5 < 4 != 3 > 4;",
-			"((5 < 4) != (3 > 4))",
+			"((5 < 4) != (3 > 4));",
 		},
 		{
 			"3 + 4 * 5 == 3 * 1 + 4 * 5;",
-			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
+			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)));",
 		},
 		{
 			"3 + 4 * 5 == 3 * 1 + 4 * 5;",
-			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
+			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)));",
 		},
 		{
 			"a + add(b * c) + d;",
-			"((a + add((b * c))) + d)",
+			"((a + add((b * c))) + d);",
 		},
 		{
 			"add(a, b, 1, 2 * 3, 4 + 5, add(6, 7 * 8));",
-			"add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)))",
+			"add(a, b, 1, (2 * 3), (4 + 5), add(6, (7 * 8)));",
 		},
 		{
 			"add(a + b + c * d / f + g)",
-			"add((((a + b) + ((c * d) / f)) + g))",
+			"add((((a + b) + ((c * d) / f)) + g));",
+		},
+		{
+			"a * [1, 2, 3, 4][b * c] * d",
+			"((a * ([1, 2, 3, 4][(b * c)])) * d);",
+		},
+		{
+			"add(a * b[2], b[1], 2 * [1, 2][1])",
+			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])));",
+		},
+		{
+			"-user.age + user.Score(1)",
+			"((-user.age) + user.Score(1));",
+		},
+		{
+			"a.b.c[0]",
+			"(a.b.c[0]);",
 		},
 	}
 
@@ -381,6 +402,7 @@ func TestParsingInfixExpression(t *testing.T) {
 
 		prog := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
 
 		if prog.String() != tt.expectedExpression {
 			t.Fatalf("expected expression string %s. got=%s",
@@ -397,6 +419,7 @@ func TestIfExpression(t *testing.T) {
 
 	prog := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
 
 	if len(prog.Statements) != 1 {
 		t.Fatalf("expected 1 statement. got=%d", len(prog.Statements))
@@ -440,6 +463,7 @@ func TestIfElseExpression(t *testing.T) {
 
 	prog := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
 
 	if len(prog.Statements) != 1 {
 		t.Fatalf("expected 1 statement. got=%d", len(prog.Statements))
@@ -492,6 +516,7 @@ func TestFunctionLiteralParsing(t *testing.T) {
 
 	prog := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
 
 	if len(prog.Statements) != 1 {
 		t.Fatalf("expected 1 statement, got=%d", len(prog.Statements))
@@ -550,6 +575,7 @@ func TestFunctionParametersParsing(t *testing.T) {
 
 		prog := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
 
 		stmt := prog.Statements[0].(*ast.ExpressionStatement)
 		fnStmt := stmt.Expression.(*ast.FunctionLiteral)
@@ -572,6 +598,7 @@ func TestCallExpressionParsing(t *testing.T) {
 
 	prog := p.ParseProgram()
 	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
 
 	if len(prog.Statements) != 1 {
 		t.Fatalf("expected 1 statement. got=%d", len(prog.Statements))
@@ -597,3 +624,159 @@ func TestCallExpressionParsing(t *testing.T) {
 	testInfixExpression(t, callExpression.Arguments[1], 2, 3, "*")
 	testInfixExpression(t, callExpression.Arguments[2], 4, 5, "+")
 }
+
+func TestStringLiteralExpression(t *testing.T) {
+	const input = `"hello \"world\"";`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	prog := p.ParseProgram()
+	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
+
+	stmt := prog.Statements[0].(*ast.ExpressionStatement)
+	literal, ok := stmt.Expression.(*ast.StringLiteral)
+	if !ok {
+		t.Fatalf("expected *ast.StringLiteral. got=%T", stmt.Expression)
+	}
+
+	const expected = `hello "world"`
+	if literal.Value != expected {
+		t.Fatalf("expected %q. got=%q", expected, literal.Value)
+	}
+
+	if literal.String() != `"hello \"world\""` {
+		t.Fatalf("expected the quoted literal. got=%s", literal.String())
+	}
+}
+
+func TestArrayLiteralParsing(t *testing.T) {
+	const input = "[1, 2 * 2, 3 + 3]"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	prog := p.ParseProgram()
+	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
+
+	stmt := prog.Statements[0].(*ast.ExpressionStatement)
+	array, ok := stmt.Expression.(*ast.ArrayLiteral)
+	if !ok {
+		t.Fatalf("expected *ast.ArrayLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(array.Elements) != 3 {
+		t.Fatalf("expected 3 elements. got=%d", len(array.Elements))
+	}
+
+	testIntegerLiteral(t, array.Elements[0], 1)
+	testInfixExpression(t, array.Elements[1], 2, 2, "*")
+	testInfixExpression(t, array.Elements[2], 3, 3, "+")
+}
+
+func TestHashLiteralParsing(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected string
+	}{
+		{`{}`, `{}`},
+		{`{"one": 1, "two": 2}`, `{"one": 1, "two": 2}`},
+		{`{true: 1 + 1, 2: x}`, `{true: (1 + 1), 2: x}`},
+	}
+
+	for _, tt := range testcases {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		prog := p.ParseProgram()
+		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
+
+		stmt := prog.Statements[0].(*ast.ExpressionStatement)
+		hash, ok := stmt.Expression.(*ast.HashLiteral)
+		if !ok {
+			t.Fatalf("expected *ast.HashLiteral. got=%T", stmt.Expression)
+		}
+
+		if hash.String() != tt.expected {
+			t.Fatalf("expected %s. got=%s", tt.expected, hash.String())
+		}
+	}
+}
+
+func TestIndexAndSelectorParsing(t *testing.T) {
+	const input = "items[1 + 1].name"
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	prog := p.ParseProgram()
+	checkParserErrors(t, p)
+	checkRoundTrip(t, prog)
+
+	stmt := prog.Statements[0].(*ast.ExpressionStatement)
+	selector, ok := stmt.Expression.(*ast.SelectorExpression)
+	if !ok {
+		t.Fatalf("expected *ast.SelectorExpression. got=%T", stmt.Expression)
+	}
+
+	testIdentifier(t, selector.Selector, "name")
+
+	index, ok := selector.Left.(*ast.IndexExpression)
+	if !ok {
+		t.Fatalf("expected *ast.IndexExpression. got=%T", selector.Left)
+	}
+
+	testIdentifier(t, index.Left, "items")
+	testInfixExpression(t, index.Index, 1, 1, "+")
+}
+
+func TestTryExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input      string
+		hasCatch   bool
+		hasFinally bool
+		expected   string
+	}{
+		{`try { x } catch (err) { err.message }`, true, false, "try { x; } catch (err) { err.message; }"},
+		{`try { x } finally { y }`, false, true, "try { x; } finally { y; }"},
+		{`try { x } catch (err) { y } finally { z }`, true, true, "try { x; } catch (err) { y; } finally { z; }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+
+		prog := p.ParseProgram()
+		checkParserErrors(t, p)
+		checkRoundTrip(t, prog)
+
+		stmt := prog.Statements[0].(*ast.ExpressionStatement)
+		try, ok := stmt.Expression.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("expected *ast.TryExpression. got=%T", stmt.Expression)
+		}
+
+		if (try.Catch != nil) != tt.hasCatch || (try.Finally != nil) != tt.hasFinally {
+			t.Fatalf("%s\n\texpected catch=%t finally=%t", tt.input, tt.hasCatch, tt.hasFinally)
+		}
+
+		if tt.hasCatch {
+			testIdentifier(t, try.Param, "err")
+		}
+
+		if try.String() != tt.expected {
+			t.Fatalf("expected %s. got=%s", tt.expected, try.String())
+		}
+	}
+
+	l := lexer.New(`try { x }`)
+	p := parser.New(l)
+	p.ParseProgram()
+
+	if len(p.Errors()) == 0 {
+		t.Fatalf("expected an error parsing a try without catch or finally")
+	}
+}
@@ -37,13 +37,18 @@ func New(l *lexer.Lexer) *Parser {
 
 	p.addPrefixParserFn(token.IDENT, p.parseIdentifier)
 	p.addPrefixParserFn(token.INT, p.parseIntegerLiteral)
+	p.addPrefixParserFn(token.FLOAT, p.parseFloatLiteral)
 	p.addPrefixParserFn(token.TRUE, p.parseBooleanLiteral)
 	p.addPrefixParserFn(token.FALSE, p.parseBooleanLiteral)
 	p.addPrefixParserFn(token.BANG, p.parsePrefixExpression)
 	p.addPrefixParserFn(token.MINUS, p.parsePrefixExpression)
 	p.addPrefixParserFn(token.LPAREN, p.parseGroupedExpression)
 	p.addPrefixParserFn(token.IF, p.parseIfExpression)
+	p.addPrefixParserFn(token.TRY, p.parseTryExpression)
 	p.addPrefixParserFn(token.FUNCTION, p.parseFunctionLiteral)
+	p.addPrefixParserFn(token.STRING, p.parseStringLiteral)
+	p.addPrefixParserFn(token.LBRACKET, p.parseArrayLiteral)
+	p.addPrefixParserFn(token.LBRACE, p.parseHashLiteral)
 
 	p.infixParsers = make(map[token.TokenType]infixParserFn)
 	p.addInfixParserFn(token.PLUS, p.parseInfixExpression)
@@ -55,6 +60,8 @@ func New(l *lexer.Lexer) *Parser {
 	p.addInfixParserFn(token.LT, p.parseInfixExpression)
 	p.addInfixParserFn(token.GT, p.parseInfixExpression)
 	p.addInfixParserFn(token.LPAREN, p.parseCallExpression)
+	p.addInfixParserFn(token.LBRACKET, p.parseIndexExpression)
+	p.addInfixParserFn(token.DOT, p.parseSelectorExpression)
 
 	return p
 }
@@ -84,6 +91,10 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
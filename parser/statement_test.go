@@ -1,6 +1,8 @@
 package parser_test
 
 import (
+	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -33,6 +35,7 @@ func TestLetStatements(t *testing.T) {
 
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, program)
 
 		if len(program.Statements) != 1 {
 			t.Fatalf("expected 1 statement. got=%d",
@@ -103,6 +106,58 @@ func checkParserErrors(t *testing.T, p *parser.Parser) {
 	}
 }
 
+// checkRoundTrip parses the program String() back and checks it
+// gives the same tree, apart from the tokens and their positions
+func checkRoundTrip(t *testing.T, program *ast.Program) {
+	t.Helper()
+
+	source := program.String()
+	p := parser.New(lexer.New(source))
+	reparsed := p.ParseProgram()
+
+	if len(p.Errors()) > 0 {
+		t.Fatalf("cannot parse %q back: %v", source, p.Errors())
+	}
+
+	if !reflect.DeepEqual(withoutTokens(t, program), withoutTokens(t, reparsed)) {
+		t.Fatalf("parsing %q back gives a different tree: %q", source, reparsed.String())
+	}
+}
+
+// withoutTokens returns the JSON encoding of the tree as
+// generic values, without the tokens of the nodes
+func withoutTokens(t *testing.T, program *ast.Program) interface{} {
+	t.Helper()
+
+	encoded, err := ast.EncodeJSON(program)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var tree interface{}
+	if err := json.Unmarshal(encoded, &tree); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var strip func(value interface{})
+	strip = func(value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			delete(value, "token")
+			for _, field := range value {
+				strip(field)
+			}
+		case []interface{}:
+			for _, element := range value {
+				strip(element)
+			}
+		}
+	}
+
+	strip(tree)
+	return tree
+}
+
 func TestReturnStatement(t *testing.T) {
 	testcases := []struct {
 		input         string
@@ -119,6 +174,7 @@ func TestReturnStatement(t *testing.T) {
 
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
+		checkRoundTrip(t, program)
 
 		if len(program.Statements) != 1 {
 			t.Fatalf("expected 1 statement. got=%d",
@@ -138,3 +194,107 @@ func TestReturnStatement(t *testing.T) {
 		testLiteralExpression(t, returnStmt.Value, tt.expectedValue)
 	}
 }
+
+func TestImportStatement(t *testing.T) {
+	const input = `import "lib/math.al" as m;`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	checkRoundTrip(t, program)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("expected 1 statement. got=%d", len(program.Statements))
+	}
+
+	importStmt, ok := program.Statements[0].(*ast.ImportStatement)
+	if !ok {
+		t.Fatalf("stmt not *ast.ImportStatement. got=%T", program.Statements[0])
+	}
+
+	if importStmt.Path.Value != "lib/math.al" {
+		t.Fatalf("expected path lib/math.al. got=%s", importStmt.Path.Value)
+	}
+
+	testIdentifier(t, importStmt.Alias, "m")
+
+	if importStmt.String() != input {
+		t.Fatalf("expected %s. got=%s", input, importStmt.String())
+	}
+}
+
+func TestThrowStatement(t *testing.T) {
+	const input = `throw limit - 1;`
+
+	l := lexer.New(input)
+	p := parser.New(l)
+
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	checkRoundTrip(t, program)
+
+	throwStmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("stmt not *ast.ThrowStatement. got=%T", program.Statements[0])
+	}
+
+	testInfixExpression(t, throwStmt.Value, "limit", 1, "-")
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x: int = 5;`, `let x: int = 5;`},
+		{`let xs: [string] = [];`, `let xs: [string] = [];`},
+		{`let h: {string: [int]} = {};`, `let h: {string: [int]} = {};`},
+		{`let add = fn(a: int, b: int) -> int { a + b };`, `let add = fn(a: int, b: int) -> int { (a + b); };`},
+		{`fn(a, b: float) { b };`, `fn(a, b: float) { b; };`},
+		{`fn() -> {string: int} { {} };`, `fn() -> {string: int} { {}; };`},
+		{`let apply: fn(fn(int) -> int, int) -> int = fn(f, x) { f(x) };`, `let apply: fn(fn(int) -> int, int) -> int = fn(f, x) { f(x); };`},
+		{`let f: fn() -> null = fn() {};`, `let f: fn() -> null = fn() {};`},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+		checkRoundTrip(t, program)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected %q. got=%q", tt.expected, program.String())
+		}
+	}
+
+	p := parser.New(lexer.New(`fn(a, b: int) { a };`))
+	function := p.ParseProgram().Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	if function.ParameterType(0) != nil || function.ParameterType(1).String() != "int" || function.Result != nil {
+		t.Errorf("unexpected annotations: %q %v", function.ParameterTypes, function.Result)
+	}
+}
+
+func TestTypeAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let x: = 5;`, "expected a type. got type ="},
+		{`let x: [int = 5;`, "expected next token type be ]. got type ="},
+		{`fn(a: {string int}) { a };`, "expected next token type be :. got type IDENT"},
+		{`fn(a) -> { a };`, "expected next token type be :. got type }"},
+		{`fn(a) -> 1 { a };`, "expected a type. got type INT"},
+		{`let f: fn(int) = 1;`, "expected next token type be ->. got type ="},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		if len(p.Errors()) == 0 || p.Errors()[0].Error() != tt.expected {
+			t.Errorf("%s: expected error %q. got=%v", tt.input, tt.expected, p.Errors())
+		}
+	}
+}
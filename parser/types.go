@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// parseTypeAnnotation parses the `: type` after a name, if there is one
+func (p *Parser) parseTypeAnnotation() (typ ast.TypeExpression, ok bool) {
+	if !p.peekTokenIs(token.COLON) {
+		return nil, true
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	typ = p.parseType()
+	return typ, typ != nil
+}
+
+// parseType parses the type starting at the current token: a name like
+// `int`, `[element]`, `{key: value}` or `fn(params...) -> result`
+func (p *Parser) parseType() ast.TypeExpression {
+	switch p.curToken.Type {
+	case token.IDENT:
+		return &ast.NamedType{Token: p.curToken, Name: p.curToken.Literal}
+
+	case token.LBRACKET:
+		array := &ast.ArrayType{Token: p.curToken}
+		p.nextToken()
+
+		if array.Element = p.parseType(); array.Element == nil || !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+
+		return array
+
+	case token.LBRACE:
+		hash := &ast.HashType{Token: p.curToken}
+		p.nextToken()
+
+		if hash.Key = p.parseType(); hash.Key == nil || !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+		if hash.Value = p.parseType(); hash.Value == nil || !p.expectPeek(token.RBRACE) {
+			return nil
+		}
+
+		return hash
+
+	case token.FUNCTION:
+		return p.parseFunctionType()
+
+	default:
+		p.errors = append(p.errors, fmt.Errorf("expected a type. got type %s", p.curToken.Type))
+		return nil
+	}
+}
+
+func (p *Parser) parseFunctionType() ast.TypeExpression {
+	function := &ast.FunctionType{Token: p.curToken, Parameters: []ast.TypeExpression{}}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RPAREN) {
+		if len(function.Parameters) > 0 && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+
+		p.nextToken()
+		param := p.parseType()
+		if param == nil {
+			return nil
+		}
+
+		function.Parameters = append(function.Parameters, param)
+	}
+
+	p.nextToken()
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.nextToken()
+	if function.Result = p.parseType(); function.Result == nil {
+		return nil
+	}
+
+	return function
+}
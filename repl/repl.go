@@ -2,9 +2,12 @@ package repl
 
 import (
 	"bufio"
-	"fmt"
+	"context"
 	"io"
+	"os"
+	"os/signal"
 
+	"github.com/EclesioMeloJunior/alang/ast"
 	"github.com/EclesioMeloJunior/alang/eval"
 	"github.com/EclesioMeloJunior/alang/lexer"
 	"github.com/EclesioMeloJunior/alang/object"
@@ -13,18 +16,23 @@ import (
 
 const PROMPT = ">> "
 
-func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
+// Start reads and evaluates the input line by line, the bindings
+// and imported modules are kept between the lines. The scripts print
+// to out and read_line reads the lines following the one being evaluated
+func Start(in io.Reader, out io.Writer, opts ...eval.Option) {
+	reader := bufio.NewReader(in)
 	env := object.NewEnv()
 
+	opts = append([]eval.Option{eval.WithStdout(out), eval.WithStdin(reader)}, opts...)
+	evaluator := eval.New(opts...)
+
 	for {
-		fmt.Print(PROMPT)
-		scanned := scanner.Scan()
-		if !scanned {
+		io.WriteString(out, PROMPT)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
 			return
 		}
 
-		line := scanner.Text()
 		l := lexer.New(line)
 		p := parser.New(l)
 
@@ -34,7 +42,9 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := eval.Eval(program, env)
+		evaluator.Prepare(program)
+
+		evaluated := evalInterruptible(evaluator, program, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
@@ -42,6 +52,15 @@ func Start(in io.Reader, out io.Writer) {
 	}
 }
 
+// evalInterruptible evaluates the program until it finishes or the user
+// hits Ctrl-C, which cancels only the current evaluation and not the REPL
+func evalInterruptible(evaluator *eval.Evaluator, program *ast.Program, env *object.Env) object.Representation {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	return evaluator.EvalContext(ctx, program, env)
+}
+
 func printParserErrors(out io.Writer, errors []error) {
 	for _, err := range errors {
 		io.WriteString(out, "\t"+err.Error()+"\n")
@@ -0,0 +1,20 @@
+package repl_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/repl"
+)
+
+func TestStartWritesToOut(t *testing.T) {
+	in := strings.NewReader("let name = read_line();\nalang\nprintln(\"hello\", name);\n")
+
+	var out strings.Builder
+	repl.Start(in, &out)
+
+	const expected = ">> >> hello alang\nnull\n>> "
+	if out.String() != expected {
+		t.Fatalf("expected output %q. got=%q", expected, out.String())
+	}
+}
@@ -0,0 +1,446 @@
+// Package resolver binds the identifiers of a program to their declarations
+// before it runs. It reports the names that are not declared, the ones used
+// before their definition and the let bindings that are never used, and
+// annotates each identifier with where its binding is, see ast.Identifier
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+type Kind int
+
+const (
+	Undeclared Kind = iota
+	UsedBeforeDefinition
+	Unused
+	UnusedParameter
+	Shadowed
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Undeclared:
+		return "Undeclared"
+	case UsedBeforeDefinition:
+		return "UsedBeforeDefinition"
+	case Unused:
+		return "Unused"
+	case UnusedParameter:
+		return "UnusedParameter"
+	case Shadowed:
+		return "Shadowed"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// Diagnostic is a problem found in the program, Pos is where the name
+// is used or, for the unused and shadowed bindings, where it is declared
+type Diagnostic struct {
+	Kind Kind
+	Pos  token.Position
+	Name string
+
+	// Shadows is where the binding hidden by a Shadowed one is declared
+	Shadows token.Position
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s", d.Pos, d.Message())
+}
+
+// Message describes the problem without its position
+func (d Diagnostic) Message() string {
+	switch d.Kind {
+	case Undeclared:
+		return "identifier not found: " + d.Name
+	case UsedBeforeDefinition:
+		return d.Name + " used before its definition"
+	case UnusedParameter:
+		return "parameter " + d.Name + " is not used"
+	case Shadowed:
+		return fmt.Sprintf("%s shadows the declaration at %s", d.Name, d.Shadows)
+	default:
+		return d.Name + " declared and not used"
+	}
+}
+
+// IsWarning reports whether the program works despite the problem, the other
+// diagnostics are errors the evaluation raises when it reaches the identifier
+func (d Diagnostic) IsWarning() bool {
+	return d.Kind != Undeclared && d.Kind != UsedBeforeDefinition
+}
+
+// Info holds what the resolver learns about the program, eg. for the linter
+type Info struct {
+	// Uses maps each identifier bound to a name declared in the program to
+	// the names declaring the binding: in lets, parameters, imports or catch
+	// clauses. A name declared again in the same scope has more declarations
+	Uses map[*ast.Identifier][]*ast.Identifier
+}
+
+// scope mirrors an environment the evaluator creates: the program scope,
+// the scope of each function call, the one binding the name of a function
+// declared by a let to itself and the scope of each catch block. The if,
+// try and finally blocks bind their names in the scope they are in
+type scope struct {
+	outer    *scope
+	global   bool
+	function bool
+
+	bindings map[string]*binding
+	names    []string // the names of the bindings, indexed by their slots
+}
+
+// declaration is how a name is declared
+type declaration int
+
+const (
+	letDeclaration declaration = iota
+	paramDeclaration
+	importDeclaration
+	catchDeclaration
+
+	// selfDeclaration is the name of a function declared
+	// by a let in the scope its function closes over
+	selfDeclaration
+)
+
+type binding struct {
+	name  string
+	slot  int
+	pos   token.Position
+	kind  declaration
+	decls []*ast.Identifier
+
+	// defined is true once the evaluation of the scope reaches the
+	// declaration, a let can be inside a branch that is not taken
+	defined bool
+	used    bool
+}
+
+type resolver struct {
+	scope       *scope
+	globals     map[string]bool
+	diagnostics []Diagnostic
+
+	// uses is kept when the caller asks for the Info
+	uses map[*ast.Identifier]*binding
+}
+
+// Resolve annotates the identifiers, function literals and catch blocks of
+// the program and returns the problems found sorted by position. The globals
+// are the names bound before the program runs, like the builtins, eg.
+// eval.BuiltinNames(), or the values set by the host. The unused bindings are
+// only reported inside the functions and catch blocks, the global ones can be
+// used by the host or the importing modules, names starting with `_` are never
+// reported. The program must have no parse errors
+func Resolve(program *ast.Program, globals ...string) []Diagnostic {
+	return ResolveInfo(program, nil, globals...)
+}
+
+// ResolveInfo resolves the program like Resolve and
+// records what it learns in the info when it is not nil
+func ResolveInfo(program *ast.Program, info *Info, globals ...string) []Diagnostic {
+	r := &resolver{globals: make(map[string]bool, len(globals))}
+	for _, name := range globals {
+		r.globals[name] = true
+	}
+
+	if info != nil {
+		r.uses = make(map[*ast.Identifier]*binding)
+	}
+
+	r.openScope(&scope{global: true})
+	r.hoist(program)
+	r.resolve(program)
+	r.closeScope()
+
+	sort.SliceStable(r.diagnostics, func(i, j int) bool {
+		a, b := r.diagnostics[i].Pos, r.diagnostics[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	if info != nil {
+		info.Uses = make(map[*ast.Identifier][]*ast.Identifier, len(r.uses))
+		for ident, b := range r.uses {
+			info.Uses[ident] = b.decls
+		}
+	}
+
+	return r.diagnostics
+}
+
+func (r *resolver) openScope(s *scope) {
+	s.outer = r.scope
+	s.bindings = make(map[string]*binding)
+	r.scope = s
+}
+
+func (r *resolver) closeScope() {
+	for _, b := range r.scope.bindings {
+		if b.used || r.scope.global || strings.HasPrefix(b.name, "_") {
+			continue
+		}
+
+		switch b.kind {
+		case letDeclaration:
+			r.report(Diagnostic{Kind: Unused, Pos: b.pos, Name: b.name})
+		case paramDeclaration:
+			r.report(Diagnostic{Kind: UnusedParameter, Pos: b.pos, Name: b.name})
+		}
+	}
+
+	r.scope = r.scope.outer
+}
+
+func (r *resolver) report(diagnostic Diagnostic) {
+	r.diagnostics = append(r.diagnostics, diagnostic)
+}
+
+// hoist declares the names the node binds in the current scope, without
+// the ones bound in the scopes it opens, so an identifier can tell a name
+// declared later in the scope from a name that is not declared at all
+func (r *resolver) hoist(node ast.Node) {
+	ast.Inspect(node, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			r.declare(node.Name, letDeclaration)
+		case *ast.ImportStatement:
+			r.declare(node.Alias, importDeclaration)
+		case *ast.FunctionLiteral:
+			return false
+		case *ast.TryExpression:
+			// the catch block has its own scope
+			r.hoist(node.Block)
+			if node.Finally != nil {
+				r.hoist(node.Finally)
+			}
+			return false
+		}
+
+		return true
+	})
+}
+
+// declare binds the name in the current scope, declaring it again reuses
+// the binding as the evaluator does. A new binding hiding the binding of
+// an outer scope is reported, unless it is the name of a function in the
+// scope the function closes over
+func (r *resolver) declare(name *ast.Identifier, kind declaration) *binding {
+	if b, ok := r.scope.bindings[name.Value]; ok {
+		b.decls = append(b.decls, name)
+		return b
+	}
+
+	b := &binding{name: name.Value, slot: len(r.scope.names), pos: name.Pos(), kind: kind}
+	b.decls = append(b.decls, name)
+	r.scope.bindings[name.Value] = b
+	r.scope.names = append(r.scope.names, name.Value)
+
+	if kind == selfDeclaration {
+		return b
+	}
+
+	for s := r.scope.outer; s != nil; s = s.outer {
+		if outer, ok := s.bindings[name.Value]; ok {
+			r.report(Diagnostic{Kind: Shadowed, Pos: b.pos, Name: b.name, Shadows: outer.pos})
+			break
+		}
+	}
+
+	return b
+}
+
+// define marks the binding of the name in the current scope as defined,
+// the names not hoisted, like the parameters, are declared by it
+func (r *resolver) define(name *ast.Identifier, kind declaration) {
+	b, ok := r.scope.bindings[name.Value]
+	if !ok {
+		b = r.declare(name, kind)
+	}
+
+	b.defined = true
+	annotate(name, r.scope, 0, b)
+}
+
+func (r *resolver) resolve(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.Program:
+		r.statements(node.Statements)
+
+	case *ast.BlockStatement:
+		r.statements(node.Statements)
+
+	case *ast.LetStatement:
+		if literal, ok := node.Value.(*ast.FunctionLiteral); ok {
+			// the function closes over a scope binding its name to itself
+			r.openScope(&scope{})
+			r.define(node.Name, selfDeclaration)
+			r.function(literal)
+			r.closeScope()
+		} else {
+			r.resolve(node.Value)
+		}
+
+		r.define(node.Name, letDeclaration)
+
+	case *ast.ImportStatement:
+		r.define(node.Alias, importDeclaration)
+
+	case *ast.ReturnStatement:
+		r.resolve(node.Value)
+
+	case *ast.ThrowStatement:
+		r.resolve(node.Value)
+
+	case *ast.ExpressionStatement:
+		r.resolve(node.Expression)
+
+	case *ast.Identifier:
+		r.use(node)
+
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.BooleanLiteral, *ast.StringLiteral:
+		// nothing to resolve
+
+	case *ast.PrefixExpression:
+		r.resolve(node.Right)
+
+	case *ast.InfixExpression:
+		r.resolve(node.Left)
+		r.resolve(node.Right)
+
+	case *ast.IfExpression:
+		r.resolve(node.Condition)
+		r.resolve(node.Consequence)
+		if node.Alternative != nil {
+			r.resolve(node.Alternative)
+		}
+
+	case *ast.FunctionLiteral:
+		r.function(node)
+
+	case *ast.CallExpression:
+		r.resolve(node.Function)
+		r.expressions(node.Arguments)
+
+	case *ast.ArrayLiteral:
+		r.expressions(node.Elements)
+
+	case *ast.HashLiteral:
+		for _, pair := range node.Pairs {
+			r.resolve(pair.Key)
+			r.resolve(pair.Value)
+		}
+
+	case *ast.IndexExpression:
+		r.resolve(node.Left)
+		r.resolve(node.Index)
+
+	case *ast.SelectorExpression:
+		// the selector is a field of the value, not a binding
+		r.resolve(node.Left)
+
+	case *ast.TryExpression:
+		r.resolve(node.Block)
+
+		if node.Catch != nil {
+			r.openScope(&scope{})
+			r.define(node.Param, catchDeclaration)
+			r.hoist(node.Catch)
+			r.resolve(node.Catch)
+
+			node.CatchScope = &ast.Scope{Names: r.scope.names}
+			r.closeScope()
+		}
+
+		if node.Finally != nil {
+			r.resolve(node.Finally)
+		}
+	}
+}
+
+func (r *resolver) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		r.resolve(stmt)
+	}
+}
+
+func (r *resolver) expressions(exps []ast.Expression) {
+	for _, exp := range exps {
+		r.resolve(exp)
+	}
+}
+
+// function resolves the literal in the scope of its calls, the
+// parameters take the first slots followed by the names of the body
+func (r *resolver) function(literal *ast.FunctionLiteral) {
+	r.openScope(&scope{function: true})
+
+	for _, param := range literal.Parameters {
+		r.define(param, paramDeclaration)
+	}
+
+	r.hoist(literal.Body)
+	r.resolve(literal.Body)
+
+	literal.Scope = &ast.Scope{Names: r.scope.names}
+	r.closeScope()
+}
+
+// use binds the identifier to the innermost binding of its name that exists
+// when the identifier is evaluated: in the scopes of the running function a
+// binding exists once its declaration is reached, in the outer scopes every
+// binding exists since a function is called after the scope declared it
+func (r *resolver) use(ident *ast.Identifier) {
+	var later *binding
+	called := false
+
+	depth := 0
+	for s := r.scope; s != nil; s = s.outer {
+		if b, ok := s.bindings[ident.Value]; ok {
+			if b.defined || called {
+				b.used = true
+				annotate(ident, s, depth, b)
+
+				if r.uses != nil {
+					r.uses[ident] = b
+				}
+				return
+			}
+
+			if later == nil {
+				later = b
+			}
+		}
+
+		called = called || s.function
+		depth++
+	}
+
+	ident.Resolved, ident.Depth, ident.Slot = false, 0, 0
+
+	switch {
+	case r.globals[ident.Value]:
+	case later != nil:
+		later.used = true
+		r.report(Diagnostic{Kind: UsedBeforeDefinition, Pos: ident.Pos(), Name: ident.Value})
+	default:
+		r.report(Diagnostic{Kind: Undeclared, Pos: ident.Pos(), Name: ident.Value})
+	}
+}
+
+func annotate(ident *ast.Identifier, s *scope, depth int, b *binding) {
+	if s.global {
+		ident.Resolved, ident.Depth, ident.Slot = false, 0, 0
+		return
+	}
+
+	ident.Resolved, ident.Depth, ident.Slot = true, depth, b.slot
+}
@@ -0,0 +1,124 @@
+package resolver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/resolver"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("unexpected parser errors: %v", p.Errors())
+	}
+
+	return program
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`let x = 1; x + len;`, nil},
+		{`x;`, []string{"1:1: identifier not found: x"}},
+		{`if (false) { y };`, []string{"1:14: identifier not found: y"}},
+		{`x; let x = 1;`, []string{"1:1: x used before its definition"}},
+		{`let x = x;`, []string{"1:9: x used before its definition"}},
+		// the function is called after the global is defined
+		{`let f = fn() { g() }; let g = fn() { 1 }; f();`, nil},
+		// the local is not defined yet, so the global is the one used
+		{`let x = 1; let f = fn() { let y = x; let x = 2; x + y }; f();`, []string{"1:42: x shadows the declaration at 1:5"}},
+		{`let f = fn() { x; let x = 1; x }; f();`, []string{"1:16: x used before its definition"}},
+		{`let f = fn(a) { let b = 1; let _c = 2; a }; f(1);`, []string{"1:21: b declared and not used"}},
+		// the global bindings can be used by the host
+		{`let a = 1;`, nil},
+		// the recursive call is not a use of the binding
+		{`let f = fn() { let g = fn(n) { g(n) }; 1 }; f();`, []string{"1:20: g declared and not used"}},
+		{`let f = fn() { if (true) { let v = 1; }; v }; f();`, nil},
+		{`try { throw 1; } catch (e) { e.message; let m = 1; };`, []string{"1:45: m declared and not used"}},
+		{`try { 1 } catch (e) { 2 }; e;`, []string{"1:28: identifier not found: e"}},
+		{`try { let t = 1; } finally { 2 }; t;`, nil},
+		{`import "lib.al" as lib; lib.v;`, nil},
+		{`host(missing);`, []string{"1:6: identifier not found: missing"}},
+		{`let f = fn(a, _b) { 1 }; f(1, 2);`, []string{"1:12: parameter a is not used"}},
+		{`let f = fn(f) { f }; f(1);`, []string{"1:12: f shadows the declaration at 1:5"}},
+		{`let f = fn() { let v = 1; let v = 2; v }; f();`, nil},
+		{`let e = 1; try { e } catch (e) { e };`, []string{"1:29: e shadows the declaration at 1:5"}},
+	}
+
+	for _, tt := range tests {
+		diagnostics := resolver.Resolve(parse(t, tt.input), "len", "host")
+
+		got := make([]string, len(diagnostics))
+		for idx, diagnostic := range diagnostics {
+			got[idx] = diagnostic.Error()
+		}
+
+		if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+			t.Errorf("%s\n\texpected %q. got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	const input = `let g = 1;
+let f = fn(a, b) {
+	let c = a;
+	try { c } catch (e) { fn() { e + b + f + g + len } }
+};`
+
+	program := parse(t, input)
+	if diagnostics := resolver.Resolve(program, "len"); len(diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", diagnostics)
+	}
+
+	type annotation struct {
+		resolved    bool
+		depth, slot int
+	}
+
+	// the identifiers in the order they appear, the inner function is
+	// in the catch scope, in the scope of f, in the scope binding f
+	expected := []annotation{
+		{false, 0, 0}, // g
+		{false, 0, 0}, // f
+		{true, 0, 0},  // a
+		{true, 0, 1},  // b
+		{true, 0, 2},  // c
+		{true, 0, 0},  // a
+		{true, 0, 2},  // c
+		{true, 0, 0},  // e
+		{true, 1, 0},  // e
+		{true, 2, 1},  // b
+		{true, 3, 0},  // f
+		{false, 0, 0}, // g
+		{false, 0, 0}, // len
+	}
+
+	var got []annotation
+	ast.Inspect(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			got = append(got, annotation{ident.Resolved, ident.Depth, ident.Slot})
+		}
+
+		return true
+	})
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d identifiers. got=%d", len(expected), len(got))
+	}
+
+	for idx := range expected {
+		if got[idx] != expected[idx] {
+			t.Errorf("identifier %d: expected %+v. got=%+v", idx, expected[idx], got[idx])
+		}
+	}
+}
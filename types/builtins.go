@@ -0,0 +1,89 @@
+package types
+
+// signatures are the types of the builtins taking a fixed number of
+// arguments, the variables given by fresh are quantified. The other
+// builtins are not checked
+var signatures = map[string]func(fresh func() Type) Type{
+	"trim":        stringsTo(1, String),
+	"upper":       stringsTo(1, String),
+	"lower":       stringsTo(1, String),
+	"contains":    stringsTo(2, Boolean),
+	"starts_with": stringsTo(2, Boolean),
+	"ends_with":   stringsTo(2, Boolean),
+	"index_of":    stringsTo(2, Integer),
+	"replace":     stringsTo(3, String),
+	"split":       stringsTo(2, &Array{Element: String}),
+	"chars":       stringsTo(1, &Array{Element: String}),
+
+	"join":   fixed(&Function{Params: []Type{&Array{Element: String}, String}, Result: String}),
+	"repeat": fixed(&Function{Params: []Type{String, Integer}, Result: String}),
+
+	// the integers are floats for these, see unify
+	"sqrt":  floatsTo(1, Float),
+	"sin":   floatsTo(1, Float),
+	"cos":   floatsTo(1, Float),
+	"tan":   floatsTo(1, Float),
+	"asin":  floatsTo(1, Float),
+	"acos":  floatsTo(1, Float),
+	"atan":  floatsTo(1, Float),
+	"atan2": floatsTo(2, Float),
+	"floor": floatsTo(1, Integer),
+	"ceil":  floatsTo(1, Integer),
+	"round": floatsTo(1, Integer),
+
+	"gcd":      fixed(&Function{Params: []Type{Integer, Integer}, Result: Integer}),
+	"rand":     fixed(&Function{Params: []Type{}, Result: Float}),
+	"rand_int": fixed(&Function{Params: []Type{Integer}, Result: Integer}),
+
+	"is_error": func(fresh func() Type) Type {
+		return &Function{Params: []Type{fresh()}, Result: Boolean}
+	},
+	"is_null": func(fresh func() Type) Type {
+		return &Function{Params: []Type{fresh()}, Result: Boolean}
+	},
+	"map": func(fresh func() Type) Type {
+		a, b := fresh(), fresh()
+		return &Function{
+			Params: []Type{&Array{Element: a}, &Function{Params: []Type{a}, Result: b}},
+			Result: &Array{Element: b},
+		}
+	},
+	"filter": predicate(func(a Type) Type { return &Array{Element: a} }),
+	"any":    predicate(func(Type) Type { return Boolean }),
+	"all":    predicate(func(Type) Type { return Boolean }),
+}
+
+func fixed(t Type) func(func() Type) Type {
+	return func(func() Type) Type {
+		return t
+	}
+}
+
+func stringsTo(arity int, result Type) func(func() Type) Type {
+	return repeated(arity, String, result)
+}
+
+func floatsTo(arity int, result Type) func(func() Type) Type {
+	return repeated(arity, Float, result)
+}
+
+func repeated(arity int, param, result Type) func(func() Type) Type {
+	params := make([]Type, arity)
+	for idx := range params {
+		params[idx] = param
+	}
+
+	return fixed(&Function{Params: params, Result: result})
+}
+
+// predicate is the type of the builtins calling a
+// function that returns a boolean for each element
+func predicate(result func(element Type) Type) func(func() Type) Type {
+	return func(fresh func() Type) Type {
+		a := fresh()
+		return &Function{
+			Params: []Type{&Array{Element: a}, &Function{Params: []Type{a}, Result: Boolean}},
+			Result: result(a),
+		}
+	}
+}
@@ -0,0 +1,620 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EclesioMeloJunior/alang/ast"
+	"github.com/EclesioMeloJunior/alang/token"
+)
+
+// Error is an operation of the program that fails with a type error
+type Error struct {
+	Pos     token.Position
+	Message string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// Check infers the types of the program and returns the errors sorted by
+// position, and the types of the names the program declares at the top level.
+//
+// The language is dynamic, so the checker is permissive: the branches of an
+// if, the elements of an array or the values a function returns may have
+// different types and then their type is not known. Neither are the types
+// of the names not declared before they are used, of the imported modules,
+// of the selectors nor of the builtins taking optional arguments. An
+// operation is only reported when its operands are known to be wrong
+func Check(program *ast.Program) (map[string]*Scheme, []Error) {
+	c := &checker{scope: &scope{names: make(map[string]*Scheme)}}
+	for name, signature := range signatures {
+		c.scope.names[name] = c.generalize(signature(c.fresh))
+	}
+
+	globals := &scope{outer: c.scope, names: make(map[string]*Scheme)}
+	c.scope = globals
+	c.statements(program.Statements)
+
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		a, b := c.errors[i].Pos, c.errors[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return globals.names, c.errors
+}
+
+// scope holds the names bound by a function call, a catch block or the
+// program, blocks do not create scopes as in the evaluation
+type scope struct {
+	outer *scope
+	names map[string]*Scheme
+}
+
+// function is the function being checked, its returns are collected
+// and joined with the value of its body unless its result is annotated
+type function struct {
+	result    Type
+	annotated bool
+	returns   []Type
+}
+
+type checker struct {
+	scope     *scope
+	functions []*function
+	errors    []Error
+	vars      int
+}
+
+func (c *checker) errorf(pos token.Position, format string, args ...interface{}) {
+	c.errors = append(c.errors, Error{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) fresh() Type {
+	c.vars++
+	return &Var{id: c.vars}
+}
+
+func (c *checker) lookup(name string) (*Scheme, bool) {
+	for s := c.scope; s != nil; s = s.outer {
+		if scheme, ok := s.names[name]; ok {
+			return scheme, true
+		}
+	}
+
+	return nil, false
+}
+
+// generalize quantifies the variables of the type that are not free in the scopes
+func (c *checker) generalize(t Type) *Scheme {
+	var bound []*Var
+	for s := c.scope; s != nil; s = s.outer {
+		for _, scheme := range s.names {
+			for _, v := range freeVars(scheme.Type, nil) {
+				if !quantified(scheme, v) {
+					bound = append(bound, v)
+				}
+			}
+		}
+	}
+
+	scheme := &Scheme{Type: t}
+	for _, v := range freeVars(t, nil) {
+		if !contains(bound, v) {
+			scheme.Vars = append(scheme.Vars, v)
+		}
+	}
+
+	return scheme
+}
+
+func (c *checker) instantiate(scheme *Scheme) Type {
+	if len(scheme.Vars) == 0 {
+		return scheme.Type
+	}
+
+	mapping := make(map[*Var]Type, len(scheme.Vars))
+	for _, v := range scheme.Vars {
+		mapping[v] = c.fresh()
+	}
+
+	return substitute(scheme.Type, mapping)
+}
+
+func quantified(scheme *Scheme, v *Var) bool {
+	return contains(scheme.Vars, v)
+}
+
+func contains(vars []*Var, v *Var) bool {
+	for _, other := range vars {
+		if other == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// join unifies the types when they are compatible, otherwise
+// the value can be of either type and its type is not known
+func (c *checker) join(ts []Type) Type {
+	if len(ts) == 0 {
+		return c.fresh()
+	}
+
+	for _, t := range ts {
+		if prune(t) == Any || !compatible(ts[0], t) {
+			return Any
+		}
+	}
+
+	for _, t := range ts[1:] {
+		unify(ts[0], t)
+	}
+
+	return ts[0]
+}
+
+// statements checks the statements and returns the type of the last one
+func (c *checker) statements(stmts []ast.Statement) Type {
+	var last Type
+	for _, stmt := range stmts {
+		last = c.statement(stmt)
+	}
+
+	if last == nil {
+		return Any
+	}
+
+	return last
+}
+
+func (c *checker) statement(stmt ast.Statement) Type {
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		c.let(stmt)
+
+	case *ast.ReturnStatement:
+		t := c.expression(stmt.Value)
+		if len(c.functions) > 0 {
+			c.result(stmt.Value, t)
+		}
+
+	case *ast.ThrowStatement:
+		c.expression(stmt.Value)
+
+	case *ast.ImportStatement:
+		c.scope.names[stmt.Alias.Value] = &Scheme{Type: Any}
+
+	case *ast.ExpressionStatement:
+		return c.expression(stmt.Expression)
+
+	case *ast.BlockStatement:
+		return c.statements(stmt.Statements)
+	}
+
+	return Any
+}
+
+func (c *checker) let(stmt *ast.LetStatement) {
+	_, isFunction := stmt.Value.(*ast.FunctionLiteral)
+
+	var annotation Type
+	if stmt.Type != nil {
+		annotation = c.annotation(stmt.Type)
+	}
+
+	// the function can call itself, its name has the
+	// type being inferred until the function is checked
+	self := annotation
+	if isFunction {
+		if self == nil {
+			self = c.fresh()
+		}
+
+		c.scope.names[stmt.Name.Value] = &Scheme{Type: self}
+	}
+
+	t := c.expression(stmt.Value)
+	if self != nil {
+		if !unify(self, t) {
+			c.errorf(stmt.Value.Pos(), "%s declared as %s, got=%s", stmt.Name.Value, self, t)
+		}
+
+		t = self
+	}
+
+	// only the functions are polymorphic, the other values are
+	// built once so the uses of their type are the same type
+	if isFunction {
+		delete(c.scope.names, stmt.Name.Value)
+		c.scope.names[stmt.Name.Value] = c.generalize(t)
+	} else {
+		c.scope.names[stmt.Name.Value] = &Scheme{Type: t}
+	}
+}
+
+// result checks a value the function being checked returns
+func (c *checker) result(value ast.Expression, t Type) {
+	fn := c.functions[len(c.functions)-1]
+	if !fn.annotated {
+		fn.returns = append(fn.returns, t)
+		return
+	}
+
+	if !unify(fn.result, t) && value != nil {
+		c.errorf(value.Pos(), "cannot return %s from a function returning %s", t, fn.result)
+	}
+}
+
+// annotation returns the type written in the annotation, the names not
+// known are reported and the `any` type is not checked
+func (c *checker) annotation(typ ast.TypeExpression) Type {
+	switch typ := typ.(type) {
+	case *ast.NamedType:
+		switch typ.Name {
+		case "int":
+			return Integer
+		case "float":
+			return Float
+		case "bool":
+			return Boolean
+		case "string":
+			return String
+		case "null":
+			return Null
+		case "any":
+			return Any
+		default:
+			c.errorf(typ.Pos(), "unknown type: %s", typ.Name)
+			return Any
+		}
+
+	case *ast.ArrayType:
+		return &Array{Element: c.annotation(typ.Element)}
+
+	case *ast.HashType:
+		return &Hash{Key: c.annotation(typ.Key), Value: c.annotation(typ.Value)}
+
+	case *ast.FunctionType:
+		params := make([]Type, len(typ.Parameters))
+		for idx, param := range typ.Parameters {
+			params[idx] = c.annotation(param)
+		}
+
+		return &Function{Params: params, Result: c.annotation(typ.Result)}
+
+	default:
+		return Any
+	}
+}
+
+func (c *checker) expression(expr ast.Expression) Type {
+	switch expr := expr.(type) {
+	case *ast.IntegerLiteral:
+		return Integer
+	case *ast.FloatLiteral:
+		return Float
+	case *ast.BooleanLiteral:
+		return Boolean
+	case *ast.StringLiteral:
+		return String
+
+	case *ast.Identifier:
+		if scheme, ok := c.lookup(expr.Value); ok {
+			return c.instantiate(scheme)
+		}
+
+		// declared later or not at all, the resolver reports it
+		return Any
+
+	case *ast.PrefixExpression:
+		return c.prefix(expr)
+
+	case *ast.InfixExpression:
+		return c.infix(expr)
+
+	case *ast.IfExpression:
+		condition := c.expression(expr.Condition)
+		if !unify(condition, Boolean) {
+			c.errorf(expr.Condition.Pos(), "condition must evaluate to a boolean, got=%s", condition)
+		}
+
+		// without an alternative the value is null when the condition is false
+		consequence := c.statements(expr.Consequence.Statements)
+		if expr.Alternative == nil {
+			return Any
+		}
+
+		return c.join([]Type{consequence, c.statements(expr.Alternative.Statements)})
+
+	case *ast.FunctionLiteral:
+		return c.function(expr)
+
+	case *ast.CallExpression:
+		return c.call(expr)
+
+	case *ast.ArrayLiteral:
+		elements := make([]Type, len(expr.Elements))
+		for idx, element := range expr.Elements {
+			elements[idx] = c.expression(element)
+		}
+
+		return &Array{Element: c.join(elements)}
+
+	case *ast.HashLiteral:
+		keys := make([]Type, len(expr.Pairs))
+		values := make([]Type, len(expr.Pairs))
+		for idx, pair := range expr.Pairs {
+			keys[idx] = c.expression(pair.Key)
+			values[idx] = c.expression(pair.Value)
+		}
+
+		return &Hash{Key: c.join(keys), Value: c.join(values)}
+
+	case *ast.IndexExpression:
+		return c.index(expr)
+
+	case *ast.SelectorExpression:
+		c.expression(expr.Left)
+		return Any
+
+	case *ast.TryExpression:
+		block := c.statements(expr.Block.Statements)
+
+		var result Type = Any
+		if expr.Catch != nil {
+			c.scope = &scope{outer: c.scope, names: map[string]*Scheme{expr.Param.Value: {Type: Any}}}
+			result = c.join([]Type{block, c.statements(expr.Catch.Statements)})
+			c.scope = c.scope.outer
+		}
+
+		if expr.Finally != nil {
+			c.statements(expr.Finally.Statements)
+		}
+
+		return result
+
+	default:
+		return Any
+	}
+}
+
+func (c *checker) prefix(expr *ast.PrefixExpression) Type {
+	right := c.expression(expr.Right)
+
+	switch expr.Operator {
+	case token.BANG:
+		return Boolean
+
+	case token.MINUS:
+		if known := name(right); known != "" && !numeric(right) {
+			c.errorf(expr.Pos(), "unknown operator: -%s", known)
+			return Any
+		}
+
+		return right
+
+	default:
+		return Any
+	}
+}
+
+// operands are the types the infix operators take, as in the evaluation
+var operands = map[string][]Basic{
+	token.PLUS:      {Integer, Float, String},
+	token.MINUS:     {Integer, Float},
+	token.ASTHERISC: {Integer, Float},
+	token.SLASH:     {Integer, Float},
+	token.LT:        {Integer, Float},
+	token.GT:        {Integer, Float},
+	token.EQ:        {Integer, Float, Boolean, String},
+	token.NOT_EQ:    {Integer, Float, Boolean, String},
+}
+
+func (c *checker) infix(expr *ast.InfixExpression) Type {
+	left := c.expression(expr.Left)
+	right := c.expression(expr.Right)
+
+	allowed, ok := operands[expr.Operator]
+	if !ok {
+		return Any
+	}
+
+	// null is compared with any value, it is only equal to null
+	if (expr.Operator == token.EQ || expr.Operator == token.NOT_EQ) && (prune(left) == Null || prune(right) == Null) {
+		return Boolean
+	}
+
+	leftName, rightName := name(left), name(right)
+	if leftName != "" && !takes(allowed, left) {
+		c.errorf(expr.Pos(), "unknown operator: %s %s %s", leftName, expr.Operator, unknown(rightName))
+		return Any
+	}
+
+	if !unify(left, right) {
+		c.errorf(expr.Pos(), "type mismatch: %s %s %s", leftName, expr.Operator, rightName)
+		return Any
+	}
+
+	if rightName != "" && !takes(allowed, right) {
+		c.errorf(expr.Pos(), "unknown operator: %s %s %s", rightName, expr.Operator, rightName)
+		return Any
+	}
+
+	switch expr.Operator {
+	case token.LT, token.GT, token.EQ, token.NOT_EQ:
+		return Boolean
+	}
+
+	if prune(left) == Float || prune(right) == Float {
+		return Float
+	}
+
+	return left
+}
+
+func takes(allowed []Basic, t Type) bool {
+	for _, basic := range allowed {
+		if prune(t) == basic {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unknown names the types not known in the messages
+func unknown(name string) string {
+	if name == "" {
+		return "?"
+	}
+
+	return name
+}
+
+func (c *checker) function(literal *ast.FunctionLiteral) Type {
+	c.scope = &scope{outer: c.scope, names: make(map[string]*Scheme, len(literal.Parameters))}
+	defer func() {
+		c.scope = c.scope.outer
+	}()
+
+	params := make([]Type, len(literal.Parameters))
+	for idx, param := range literal.Parameters {
+		if typ := literal.ParameterType(idx); typ != nil {
+			params[idx] = c.annotation(typ)
+		} else {
+			params[idx] = c.fresh()
+		}
+
+		c.scope.names[param.Value] = &Scheme{Type: params[idx]}
+	}
+
+	fn := &function{result: c.fresh()}
+	if literal.Result != nil {
+		fn.result = c.annotation(literal.Result)
+		fn.annotated = true
+	}
+
+	c.functions = append(c.functions, fn)
+	body := c.statements(literal.Body.Statements)
+
+	// the body ending in a return has no value of its own
+	stmts := literal.Body.Statements
+	if len(stmts) > 0 {
+		if _, ok := stmts[len(stmts)-1].(*ast.ReturnStatement); !ok {
+			c.result(lastValue(stmts), body)
+		}
+	}
+
+	c.functions = c.functions[:len(c.functions)-1]
+
+	if !fn.annotated {
+		if joined := c.join(fn.returns); joined == Any {
+			fn.result = Any
+		} else {
+			unify(fn.result, joined)
+		}
+	}
+
+	return &Function{Params: params, Result: fn.result}
+}
+
+// lastValue is the expression giving the value of the statements, if any
+func lastValue(stmts []ast.Statement) ast.Expression {
+	if stmt, ok := stmts[len(stmts)-1].(*ast.ExpressionStatement); ok {
+		return stmt.Expression
+	}
+
+	return nil
+}
+
+func (c *checker) call(expr *ast.CallExpression) Type {
+	callee := c.expression(expr.Function)
+
+	args := make([]Type, len(expr.Arguments))
+	for idx, arg := range expr.Arguments {
+		args[idx] = c.expression(arg)
+	}
+
+	calleeName := "function"
+	if ident, ok := expr.Function.(*ast.Identifier); ok {
+		calleeName = ident.Value
+	}
+
+	if prune(callee) == Any {
+		return Any
+	}
+
+	switch fn := prune(callee).(type) {
+	case *Function:
+		if len(fn.Params) != len(args) {
+			c.errorf(expr.Pos(), "wrong number of arguments to %s: expected %d, got=%d", calleeName, len(fn.Params), len(args))
+			return fn.Result
+		}
+
+		for idx, param := range fn.Params {
+			if !unify(param, args[idx]) {
+				c.errorf(expr.Arguments[idx].Pos(), "argument %d to %s must be %s, got=%s", idx+1, calleeName, param, args[idx])
+			}
+		}
+
+		return fn.Result
+
+	case *Var:
+		// the variable only fails to unify when it occurs in the
+		// arguments, eg. fn(x) { x(x) }, its type would never end
+		result := c.fresh()
+		if !unify(fn, &Function{Params: args, Result: result}) {
+			c.errorf(expr.Pos(), "infinite type: %s would contain itself", calleeName)
+		}
+
+		return result
+
+	default:
+		c.errorf(expr.Pos(), "not a function: %s", name(callee))
+		return Any
+	}
+}
+
+func (c *checker) index(expr *ast.IndexExpression) Type {
+	left := c.expression(expr.Left)
+	index := c.expression(expr.Index)
+
+	if prune(left) == Any {
+		return Any
+	}
+
+	switch collection := prune(left).(type) {
+	case *Array:
+		if !unify(index, Integer) {
+			c.errorf(expr.Index.Pos(), "array index must be an INTEGER, got=%s", index)
+		}
+
+		return collection.Element
+
+	case *Hash:
+		// a key of another type is not found, the value is null
+		if compatible(index, collection.Key) {
+			unify(index, collection.Key)
+		}
+
+		return collection.Value
+
+	case *Var:
+		return Any
+
+	default:
+		if collection == String {
+			if !unify(index, Integer) {
+				c.errorf(expr.Index.Pos(), "string index must be an INTEGER, got=%s", index)
+			}
+
+			return String
+		}
+
+		c.errorf(expr.Pos(), "index operator not supported: %s", name(left))
+		return Any
+	}
+}
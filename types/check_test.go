@@ -0,0 +1,102 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/EclesioMeloJunior/alang/lexer"
+	"github.com/EclesioMeloJunior/alang/parser"
+	"github.com/EclesioMeloJunior/alang/types"
+)
+
+func TestCheckErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`let add = fn(a, b) { a + b }; add(1, 2.5); add("a", "b");`, nil},
+		{`let x = 1 + true;`, []string{"1:9: type mismatch: INTEGER + BOOLEAN"}},
+		{`"a" - "b";`, []string{"1:1: unknown operator: STRING - STRING"}},
+		{`if (1) { 2 }`, []string{"1:5: condition must evaluate to a boolean, got=INTEGER"}},
+		{`let x = 5; x(1);`, []string{"1:12: not a function: INTEGER"}},
+		{`let a = [1, 2]; a["x"];`, []string{"1:19: array index must be an INTEGER, got=STRING"}},
+		{`upper(5);`, []string{"1:7: argument 1 to upper must be STRING, got=INTEGER"}},
+		{`let f = fn(a) { a }; f(1, 2);`, []string{"1:22: wrong number of arguments to f: expected 1, got=2"}},
+		{`let n: int = "one";`, []string{"1:14: n declared as INTEGER, got=STRING"}},
+		{`let f = fn(a: int) -> string { a }; f(1);`, []string{"1:32: cannot return INTEGER from a function returning STRING"}},
+		{`let f = fn(a: int) { a }; f("1");`, []string{"1:29: argument 1 to f must be INTEGER, got=STRING"}},
+		{`let x: number = 1;`, []string{"1:8: unknown type: number"}},
+		{`let f = fn(x) { x(x) };`, []string{"1:17: infinite type: x would contain itself"}},
+		{`let f = fn(x) { x([x]) };`, []string{"1:17: infinite type: x would contain itself"}},
+		{`let f = fn(x, y) { x(y) };`, nil},
+		{`let f = fn(a: null, b: string) { a == b };`, nil},
+		{`let f = fn(a: null) { a + 1 };`, []string{"1:23: unknown operator: NULL + INTEGER"}},
+		// the branches can have different types, the value is not known
+		{`let v = if (rand() < 0.5) { 1 } else { "one" }; v + 1; v + "s";`, nil},
+		{`let h = {"name": "alang", "age": 3}; h["age"] + 1;`, nil},
+		{`let x: any = 1; x + "s";`, nil},
+		{`try { 1 / 0 } catch (e) { e.message + 1 }`, nil},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%s: unexpected parser errors: %v", tt.input, p.Errors())
+		}
+
+		_, errs := types.Check(program)
+		if len(errs) != len(tt.expected) {
+			t.Fatalf("%s: expected %d errors, got=%d: %v", tt.input, len(tt.expected), len(errs), errs)
+		}
+
+		for idx, err := range errs {
+			if err.Error() != tt.expected[idx] {
+				t.Errorf("%s: expected error %q, got=%q", tt.input, tt.expected[idx], err.Error())
+			}
+		}
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		expected string
+	}{
+		{`let x = 1;`, "x", "INTEGER"},
+		{`let x = 1 + 2.5;`, "x", "FLOAT"},
+		{`let s = upper("a") + "b";`, "s", "STRING"},
+		{`let id = fn(a) { a }; id(1); id("a");`, "id", "FUNCTION(a) -> a"},
+		{`let compose = fn(f, g) { fn(x) { f(g(x)) } };`, "compose",
+			"FUNCTION(FUNCTION(a) -> b, FUNCTION(c) -> a) -> FUNCTION(c) -> b"},
+		{`let fib = fn(n) { if (n < 2) { return n; } fib(n - 1) + fib(n - 2) };`, "fib", "FUNCTION(INTEGER) -> INTEGER"},
+		{`let xs = map([1, 2], fn(x) { x * 2 });`, "xs", "ARRAY[INTEGER]"},
+		{`let h = {"a": 1};`, "h", "HASH[STRING, INTEGER]"},
+		{`let h = {"name": "alang", "age": 3};`, "h", "HASH[STRING, ANY]"},
+		{`let f = fn(a: [int], key: string) -> {string: int} { {key: a[0]} };`, "f",
+			"FUNCTION(ARRAY[INTEGER], STRING) -> HASH[STRING, INTEGER]"},
+		{`let maybe = fn(n) { if (n > 0) { "pos" } else { 0 } };`, "maybe", "FUNCTION(INTEGER) -> ANY"},
+	}
+
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%s: unexpected parser errors: %v", tt.input, p.Errors())
+		}
+
+		globals, errs := types.Check(program)
+		if len(errs) != 0 {
+			t.Fatalf("%s: unexpected errors: %v", tt.input, errs)
+		}
+
+		scheme, ok := globals[tt.name]
+		if !ok {
+			t.Fatalf("%s: %s not declared", tt.input, tt.name)
+		}
+
+		if scheme.String() != tt.expected {
+			t.Errorf("%s: expected %s to be %s, got=%s", tt.input, tt.name, tt.expected, scheme)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Package types infers the types of alang programs, Hindley-Milner style,
+// and reports the operations that fail with a type error when evaluated.
+// The annotations are optional: the types not written are inferred
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the type of a value, the names are the ones of the
+// object types, eg. INTEGER, so the errors read like the runtime ones
+type Type interface {
+	String() string
+}
+
+// Basic are the types without parameters
+type Basic string
+
+const (
+	Integer Basic = "INTEGER"
+	Float   Basic = "FLOAT"
+	Boolean Basic = "BOOLEAN"
+	String  Basic = "STRING"
+	Null    Basic = "NULL"
+
+	// Any is the type of the values the checker does not know, eg. the
+	// values of a hash of mixed types. It unifies with any type without
+	// binding it, so each use of the value can be of a different type
+	Any Basic = "ANY"
+)
+
+func (b Basic) String() string {
+	return string(b)
+}
+
+// Array is the type of the arrays whose elements are of the same type
+type Array struct {
+	Element Type
+}
+
+func (a *Array) String() string {
+	return "ARRAY[" + a.Element.String() + "]"
+}
+
+// Hash is the type of the hashes whose keys and values are of the same types
+type Hash struct {
+	Key, Value Type
+}
+
+func (h *Hash) String() string {
+	return "HASH[" + h.Key.String() + ", " + h.Value.String() + "]"
+}
+
+// Function is the type of the functions
+type Function struct {
+	Params []Type
+	Result Type
+}
+
+func (f *Function) String() string {
+	params := make([]string, len(f.Params))
+	for idx, param := range f.Params {
+		params[idx] = param.String()
+	}
+
+	return "FUNCTION(" + strings.Join(params, ", ") + ") -> " + f.Result.String()
+}
+
+// Var is a type not known yet, unifying it with a type makes it that type
+type Var struct {
+	id       int
+	instance Type
+}
+
+func (v *Var) String() string {
+	if v.instance != nil {
+		return v.instance.String()
+	}
+
+	return fmt.Sprintf("T%d", v.id)
+}
+
+// prune returns the type the variables are bound to
+func prune(t Type) Type {
+	for {
+		v, ok := t.(*Var)
+		if !ok || v.instance == nil {
+			return t
+		}
+
+		t = v.instance
+	}
+}
+
+// name is how the runtime names the type of the values, empty when not known
+func name(t Type) string {
+	switch t := prune(t).(type) {
+	case Basic:
+		if t == Any {
+			return ""
+		}
+		return string(t)
+	case *Array:
+		return "ARRAY"
+	case *Hash:
+		return "HASH"
+	case *Function:
+		return "FUNCTION"
+	default:
+		return ""
+	}
+}
+
+func numeric(t Type) bool {
+	t = prune(t)
+	return t == Integer || t == Float
+}
+
+// unify makes the types the same binding their variables, it returns false
+// when they cannot be. Integers and floats mix in the arithmetic and the
+// comparisons, so they unify
+func unify(a, b Type) bool {
+	a, b = prune(a), prune(b)
+
+	if a == Any || b == Any {
+		return true
+	}
+
+	if v, ok := a.(*Var); ok {
+		if a == b {
+			return true
+		}
+
+		if occurs(v, b) {
+			return false
+		}
+
+		v.instance = b
+		return true
+	}
+
+	if _, ok := b.(*Var); ok {
+		return unify(b, a)
+	}
+
+	switch a := a.(type) {
+	case Basic:
+		return a == b || numeric(a) && numeric(b)
+
+	case *Array:
+		other, ok := b.(*Array)
+		return ok && unify(a.Element, other.Element)
+
+	case *Hash:
+		other, ok := b.(*Hash)
+		return ok && unify(a.Key, other.Key) && unify(a.Value, other.Value)
+
+	case *Function:
+		other, ok := b.(*Function)
+		if !ok || len(a.Params) != len(other.Params) {
+			return false
+		}
+
+		for idx := range a.Params {
+			if !unify(a.Params[idx], other.Params[idx]) {
+				return false
+			}
+		}
+
+		return unify(a.Result, other.Result)
+	}
+
+	return false
+}
+
+// compatible reports whether the types unify without binding any variable,
+// the variables are taken as any type
+func compatible(a, b Type) bool {
+	a, b = prune(a), prune(b)
+
+	if a == Any || b == Any {
+		return true
+	}
+
+	_, aVar := a.(*Var)
+	_, bVar := b.(*Var)
+	if aVar || bVar {
+		return true
+	}
+
+	switch a := a.(type) {
+	case Basic:
+		return a == b || numeric(a) && numeric(b)
+
+	case *Array:
+		other, ok := b.(*Array)
+		return ok && compatible(a.Element, other.Element)
+
+	case *Hash:
+		other, ok := b.(*Hash)
+		return ok && compatible(a.Key, other.Key) && compatible(a.Value, other.Value)
+
+	case *Function:
+		other, ok := b.(*Function)
+		if !ok || len(a.Params) != len(other.Params) {
+			return false
+		}
+
+		for idx := range a.Params {
+			if !compatible(a.Params[idx], other.Params[idx]) {
+				return false
+			}
+		}
+
+		return compatible(a.Result, other.Result)
+	}
+
+	return false
+}
+
+func occurs(v *Var, t Type) bool {
+	switch t := prune(t).(type) {
+	case *Var:
+		return t == v
+	case *Array:
+		return occurs(v, t.Element)
+	case *Hash:
+		return occurs(v, t.Key) || occurs(v, t.Value)
+	case *Function:
+		for _, param := range t.Params {
+			if occurs(v, param) {
+				return true
+			}
+		}
+		return occurs(v, t.Result)
+	default:
+		return false
+	}
+}
+
+// freeVars appends the variables of the type not bound yet
+func freeVars(t Type, vars []*Var) []*Var {
+	switch t := prune(t).(type) {
+	case *Var:
+		for _, v := range vars {
+			if v == t {
+				return vars
+			}
+		}
+		return append(vars, t)
+	case *Array:
+		return freeVars(t.Element, vars)
+	case *Hash:
+		return freeVars(t.Value, freeVars(t.Key, vars))
+	case *Function:
+		for _, param := range t.Params {
+			vars = freeVars(param, vars)
+		}
+		return freeVars(t.Result, vars)
+	default:
+		return vars
+	}
+}
+
+// Scheme is the type of a name, the variables in Vars
+// are replaced by new ones each time the name is used
+type Scheme struct {
+	Vars []*Var
+	Type Type
+}
+
+// String prints the type with its variables named a, b, c...
+func (s *Scheme) String() string {
+	names := make(map[*Var]Type)
+	for idx, v := range freeVars(s.Type, nil) {
+		names[v] = Basic(string(rune('a' + idx%26)))
+	}
+
+	return substitute(s.Type, names).String()
+}
+
+// substitute replaces the variables in the mapping
+func substitute(t Type, mapping map[*Var]Type) Type {
+	switch t := prune(t).(type) {
+	case *Var:
+		if replacement, ok := mapping[t]; ok {
+			return replacement
+		}
+		return t
+	case *Array:
+		return &Array{Element: substitute(t.Element, mapping)}
+	case *Hash:
+		return &Hash{Key: substitute(t.Key, mapping), Value: substitute(t.Value, mapping)}
+	case *Function:
+		params := make([]Type, len(t.Params))
+		for idx, param := range t.Params {
+			params[idx] = substitute(param, mapping)
+		}
+		return &Function{Params: params, Result: substitute(t.Result, mapping)}
+	default:
+		return t
+	}
+}